@@ -2,25 +2,34 @@ package main
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
-	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/go-metrics"
 	"github.com/hashicorp/go-metrics/prometheus"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"kvstore/internal/admin"
+	"kvstore/internal/audit"
+	"kvstore/internal/auth"
+	"kvstore/internal/certs"
 	"kvstore/internal/config"
 	"kvstore/internal/core"
+	"kvstore/internal/discovery"
 	"kvstore/internal/grpc/clients"
 	"kvstore/internal/grpc/servers"
+	"kvstore/internal/grpc/transport"
 	"kvstore/internal/raft"
+	"kvstore/internal/ratelimit"
 	"kvstore/internal/sl"
+	"kvstore/internal/slowlog"
+	"kvstore/internal/tracing"
 	"log"
 	"log/slog"
-	"net/http"
+	"net"
 	"os"
 	"os/signal"
+	"time"
 )
 
-// рефактор raft.New todo передавать таймаут для выборов итд
+const tracesFlushTimeout = 5 * time.Second
 
 func main() {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
@@ -31,17 +40,60 @@ func main() {
 		log.Fatalf("cannot read config file: %s", err)
 	}
 
-	logger := slog.New(slog.NewJSONHandler(os.Stdout, conf.Logger()))
+	levels, err := conf.LogLevels()
+	if err != nil {
+		log.Fatalf("cannot read log levels: %s", err)
+	}
+
+	logger := slog.New(sl.NewLevelHandler(slog.NewJSONHandler(os.Stdout, conf.Logger()), levels))
 
 	cl := logger.With(sl.Component("di"))
 
+	if err := sl.SetRedaction(conf.Redaction()); err != nil {
+		cl.Error("cannot set redaction of logs", sl.Error(err))
+		return
+	}
+
+	traces, err := tracing.New(ctx, logger, conf.Tracing())
+	if err != nil {
+		cl.Error("cannot create tracing", sl.Error(err))
+		return
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), tracesFlushTimeout)
+		defer cancel()
+
+		if err := traces.Shutdown(ctx); err != nil {
+			cl.Error("cannot shutdown tracing", sl.Error(err))
+		}
+	}()
+
+	var (
+		certsReloader        *certs.Reloader
+		serverTLS, clientTLS *tls.Config
+	)
+	if conf.TLSEnabled() {
+		certsReloader, err = certs.New(logger, conf.TLS())
+		if err != nil {
+			cl.Error("cannot load certificates", sl.Error(err))
+			return
+		}
+		serverTLS, clientTLS = certsReloader.ServerConfig(), certsReloader.ClientConfig()
+	}
+
 	store, err := core.NewStore(logger, conf.Store())
 	if err != nil {
 		cl.Error("cannot create store", sl.Error(err))
 		return
 	}
 
-	existLeader, err := clients.NewRaftClient(conf.ExistingRaftClient())
+	peers, err := discovery.New(logger, net.DefaultResolver, conf.Discovery())
+	if err != nil {
+		cl.Error("cannot create discovery", sl.Error(err))
+		return
+	}
+
+	existLeader, err := clients.NewRaftClient(peers, clientTLS, conf.ExistingRaftClient())
 	if errors.Is(err, clients.ErrAddressIsEmpty) {
 		cl.Warn("no cluster node to join to cluster was provided. It is ok if you want to bootstrap cluster")
 	}
@@ -49,39 +101,123 @@ func main() {
 		cl.Error("cannot create raft client", sl.Error(err))
 		return
 	}
+	if existLeader != nil {
+		defer func() {
+			if err := existLeader.Close(); err != nil {
+				cl.Error("cannot close raft client", sl.Error(err))
+			}
+		}()
+	}
 
-	fsm, err := raft.NewFSM(logger, store)
+	users := auth.NewUsers()
+	joinTokens := auth.NewJoinTokens()
+	rateLimits := ratelimit.NewOverrides()
+
+	limiter, err := ratelimit.New(logger, rateLimits, conf.RateLimit())
 	if err != nil {
-		cl.Error("cannot create FSM", sl.Error(err))
+		cl.Error("cannot create rate limiter", sl.Error(err))
 		return
 	}
 
-	hcLogger := hclog.New(conf.HashicorpLogger())
+	authenticator, err := auth.NewAuthenticator(logger, users, conf.Authenticator())
+	if err != nil {
+		cl.Error("cannot create authenticator", sl.Error(err))
+		return
+	}
 
-	r, recovered, err := raft.New(logger, hcLogger, fsm, conf.Raft())
+	tokens, err := auth.NewTokens(logger, conf.Tokens())
 	if err != nil {
-		cl.Error("cannot create raft instance", sl.Error(err))
+		cl.Error("cannot create tokens", sl.Error(err))
 		return
 	}
 
-	distributedStore, err := raft.NewStore(logger, r, store)
+	policy, err := auth.NewPolicy(logger, conf.Policy())
 	if err != nil {
-		cl.Error("cannot create distributed store", sl.Error(err))
+		cl.Error("cannot create policy", sl.Error(err))
 		return
 	}
 
-	clusterNode, err := raft.NewClusterNode(logger, r, existLeader, conf.ClusterNode())
+	events, err := raft.NewEvents(logger, raft.ServerID(conf.Raft().NodeID))
 	if err != nil {
-		cl.Error("cannot create cluster node", sl.Error(err))
+		cl.Error("cannot create cluster events", sl.Error(err))
+		return
+	}
+
+	directory := raft.NewDirectory()
+
+	fsm, err := raft.NewFSM(logger, store, users, joinTokens, rateLimits, directory, events, conf.SizeLimits())
+	if err != nil {
+		cl.Error("cannot create FSM", sl.Error(err))
 		return
 	}
 
-	srv, err := servers.New(logger, conf.GRPCServer())
+	hcLogger := sl.NewHCLogger(logger, levels, "hashicorp.Raft.(raft.internal)")
+
+	auditLog, err := audit.New(logger, conf.Audit())
+	if err != nil {
+		cl.Error("cannot create audit log", sl.Error(err))
+		return
+	}
+	defer func() {
+		if err := auditLog.Close(); err != nil {
+			cl.Error("cannot close audit log", sl.Error(err))
+		}
+	}()
+
+	slowLog, err := slowlog.New(logger, conf.SlowLog())
+	if err != nil {
+		cl.Error("cannot create slow log", sl.Error(err))
+		return
+	}
+
+	srv, err := servers.New(logger, servers.Dependencies{
+		Authenticator: authenticator,
+		Tokens:        tokens,
+		Policy:        policy,
+		Limiter:       limiter,
+		Auditor:       auditLog,
+		SlowLog:       slowLog,
+	}, serverTLS, conf.GRPCServer())
 	if err != nil {
 		cl.Error("cannot create server", sl.Error(err))
 		return
 	}
 
+	var raftTransport raft.Transport
+	if conf.RaftOverGRPC() {
+		grpcTransport, err := transport.New(logger, clientTLS, conf.RaftTransport())
+		if err != nil {
+			cl.Error("cannot create grpc raft transport", sl.Error(err))
+			return
+		}
+		grpcTransport.RegisterTo(srv.Server)
+		raftTransport = grpcTransport
+	} else {
+		raftTransport, err = raft.NewTCPTransport(hcLogger, serverTLS, clientTLS, conf.Raft())
+		if err != nil {
+			cl.Error("cannot create tcp raft transport", sl.Error(err))
+			return
+		}
+	}
+
+	r, hasState, err := raft.New(logger, hcLogger, fsm, raftTransport, events, conf.Raft())
+	if err != nil {
+		cl.Error("cannot create raft instance", sl.Error(err))
+		return
+	}
+
+	distributedStore, err := raft.NewStore(logger, r, store, directory, slowLog, conf.SizeLimits())
+	if err != nil {
+		cl.Error("cannot create distributed store", sl.Error(err))
+		return
+	}
+
+	clusterNode, err := raft.NewClusterNode(logger, r, existLeader, distributedStore, joinTokens, directory, conf.ClusterNode())
+	if err != nil {
+		cl.Error("cannot create cluster node", sl.Error(err))
+		return
+	}
+
 	raftServer, err := servers.NewRaftServer(clusterNode)
 	if err != nil {
 		cl.Error("cannot create raft grpc server", sl.Error(err))
@@ -89,14 +225,39 @@ func main() {
 	}
 	raftServer.RegisterTo(srv.Server)
 
-	kvstoreServer, err := servers.NewKVStoreServer(distributedStore)
+	kvstoreServer, err := servers.NewKVStoreServer(distributedStore, conf.SizeLimits())
 	if err != nil {
 		cl.Error("cannot create kvstore grpc server", sl.Error(err))
 		return
 	}
 	kvstoreServer.RegisterTo(srv.Server)
 
-	healthServer := servers.NewHealthServer()
+	adminServer, err := servers.NewAdminServer(distributedStore, users, rateLimits, policy, levels, events)
+	if err != nil {
+		cl.Error("cannot create admin grpc server", sl.Error(err))
+		return
+	}
+	adminServer.RegisterTo(srv.Server)
+
+	authServer, err := servers.NewAuthServer(authenticator, tokens)
+	if err != nil {
+		cl.Error("cannot create auth grpc server", sl.Error(err))
+		return
+	}
+	authServer.RegisterTo(srv.Server)
+
+	usageServer, err := servers.NewUsageServer(store, policy)
+	if err != nil {
+		cl.Error("cannot create usage grpc server", sl.Error(err))
+		return
+	}
+	usageServer.RegisterTo(srv.Server)
+
+	healthServer, err := servers.NewHealthServer(logger, clusterNode)
+	if err != nil {
+		cl.Error("cannot create health grpc server", sl.Error(err))
+		return
+	}
 	healthServer.RegisterTo(srv.Server)
 
 	promSink, err := prometheus.NewPrometheusSink()
@@ -114,13 +275,63 @@ func main() {
 		return
 	}
 
+	httpServer, err := admin.New(logger, clusterNode, authenticator, policy, levels, serverTLS, conf.AdminHTTP())
+	if err != nil {
+		cl.Error("cannot create admin http server", sl.Error(err))
+		return
+	}
+
 	go func() {
-		if err := clusterNode.Run(ctx, recovered); err != nil {
+		if err := clusterNode.Run(ctx, hasState); err != nil {
 			cl.Error("cannot start cluster node", sl.Error(err))
 			stop()
 		}
 	}()
 
+	go func() {
+		if err := clusterNode.RunRegistration(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			cl.Error("cannot start registering client address", sl.Error(err))
+			stop()
+		}
+	}()
+
+	if certsReloader != nil {
+		go func() {
+			if err := certsReloader.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+				cl.Error("cannot start reloading certificates", sl.Error(err))
+				stop()
+			}
+		}()
+	}
+
+	go func() {
+		if err := peers.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			cl.Error("cannot start discovery", sl.Error(err))
+			stop()
+		}
+	}()
+
+	go func() {
+		if err := limiter.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			cl.Error("cannot start cleaning rate limiter", sl.Error(err))
+			stop()
+		}
+	}()
+
+	go func() {
+		if err := events.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			cl.Error("cannot start publishing cluster events", sl.Error(err))
+			stop()
+		}
+	}()
+
+	go func() {
+		if err := healthServer.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+			cl.Error("cannot start updating health", sl.Error(err))
+			stop()
+		}
+	}()
+
 	go func() {
 		if err := srv.Run(); err != nil {
 			cl.Error("cannot start server", sl.Error(err))
@@ -136,8 +347,10 @@ func main() {
 	}()
 
 	go func() {
-		http.Handle("/metrics", promhttp.Handler())
-		log.Fatal(http.ListenAndServe(":9090", nil))
+		if err := httpServer.Run(); err != nil {
+			cl.Error("cannot start admin http server", sl.Error(err))
+			stop()
+		}
 	}()
 
 	<-ctx.Done()
@@ -146,6 +359,10 @@ func main() {
 		cl.Error("cannot shutdown server", sl.Error(err))
 	}
 
+	if err := httpServer.Shutdown(); err != nil {
+		cl.Error("cannot shutdown admin http server", sl.Error(err))
+	}
+
 	if err := clusterNode.Shutdown(); err != nil {
 		logger.Error("cannot shutdown cluster", sl.Error(err))
 	}
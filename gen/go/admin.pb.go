@@ -0,0 +1,1371 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.21.12
+// source: admin.proto
+
+package kvpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type CreateUserIn struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	Roles         []string               `protobuf:"bytes,3,rep,name=roles,proto3" json:"roles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserIn) Reset() {
+	*x = CreateUserIn{}
+	mi := &file_admin_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserIn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserIn) ProtoMessage() {}
+
+func (x *CreateUserIn) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserIn.ProtoReflect.Descriptor instead.
+func (*CreateUserIn) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *CreateUserIn) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateUserIn) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+func (x *CreateUserIn) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+type CreateUserOut struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateUserOut) Reset() {
+	*x = CreateUserOut{}
+	mi := &file_admin_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateUserOut) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateUserOut) ProtoMessage() {}
+
+func (x *CreateUserOut) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateUserOut.ProtoReflect.Descriptor instead.
+func (*CreateUserOut) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{1}
+}
+
+type DeleteUserIn struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUserIn) Reset() {
+	*x = DeleteUserIn{}
+	mi := &file_admin_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserIn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserIn) ProtoMessage() {}
+
+func (x *DeleteUserIn) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserIn.ProtoReflect.Descriptor instead.
+func (*DeleteUserIn) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *DeleteUserIn) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+type DeleteUserOut struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteUserOut) Reset() {
+	*x = DeleteUserOut{}
+	mi := &file_admin_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteUserOut) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteUserOut) ProtoMessage() {}
+
+func (x *DeleteUserOut) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteUserOut.ProtoReflect.Descriptor instead.
+func (*DeleteUserOut) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{3}
+}
+
+type ChangePasswordIn struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Password      string                 `protobuf:"bytes,2,opt,name=password,proto3" json:"password,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangePasswordIn) Reset() {
+	*x = ChangePasswordIn{}
+	mi := &file_admin_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordIn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordIn) ProtoMessage() {}
+
+func (x *ChangePasswordIn) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordIn.ProtoReflect.Descriptor instead.
+func (*ChangePasswordIn) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *ChangePasswordIn) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ChangePasswordIn) GetPassword() string {
+	if x != nil {
+		return x.Password
+	}
+	return ""
+}
+
+type ChangePasswordOut struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangePasswordOut) Reset() {
+	*x = ChangePasswordOut{}
+	mi := &file_admin_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangePasswordOut) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangePasswordOut) ProtoMessage() {}
+
+func (x *ChangePasswordOut) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangePasswordOut.ProtoReflect.Descriptor instead.
+func (*ChangePasswordOut) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{5}
+}
+
+type ListUsersIn struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersIn) Reset() {
+	*x = ListUsersIn{}
+	mi := &file_admin_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersIn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersIn) ProtoMessage() {}
+
+func (x *ListUsersIn) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersIn.ProtoReflect.Descriptor instead.
+func (*ListUsersIn) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{6}
+}
+
+type ListUsersOut struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*UserInfo            `protobuf:"bytes,1,rep,name=users,proto3" json:"users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListUsersOut) Reset() {
+	*x = ListUsersOut{}
+	mi := &file_admin_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListUsersOut) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListUsersOut) ProtoMessage() {}
+
+func (x *ListUsersOut) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListUsersOut.ProtoReflect.Descriptor instead.
+func (*ListUsersOut) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ListUsersOut) GetUsers() []*UserInfo {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+type UserInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Roles         []string               `protobuf:"bytes,2,rep,name=roles,proto3" json:"roles,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UserInfo) Reset() {
+	*x = UserInfo{}
+	mi := &file_admin_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UserInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UserInfo) ProtoMessage() {}
+
+func (x *UserInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UserInfo.ProtoReflect.Descriptor instead.
+func (*UserInfo) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *UserInfo) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UserInfo) GetRoles() []string {
+	if x != nil {
+		return x.Roles
+	}
+	return nil
+}
+
+type CreateJoinTokenIn struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// ttl_seconds is lifetime of the token, one hour is used if it is not provided
+	TtlSeconds    int64 `protobuf:"varint,1,opt,name=ttl_seconds,json=ttlSeconds,proto3" json:"ttl_seconds,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateJoinTokenIn) Reset() {
+	*x = CreateJoinTokenIn{}
+	mi := &file_admin_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateJoinTokenIn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateJoinTokenIn) ProtoMessage() {}
+
+func (x *CreateJoinTokenIn) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateJoinTokenIn.ProtoReflect.Descriptor instead.
+func (*CreateJoinTokenIn) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *CreateJoinTokenIn) GetTtlSeconds() int64 {
+	if x != nil {
+		return x.TtlSeconds
+	}
+	return 0
+}
+
+type CreateJoinTokenOut struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// token is shown only once, it is passed to the joining node with -join-token flag
+	Token string `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	// expires_at is unix time in seconds
+	ExpiresAt     int64 `protobuf:"varint,2,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateJoinTokenOut) Reset() {
+	*x = CreateJoinTokenOut{}
+	mi := &file_admin_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateJoinTokenOut) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateJoinTokenOut) ProtoMessage() {}
+
+func (x *CreateJoinTokenOut) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateJoinTokenOut.ProtoReflect.Descriptor instead.
+func (*CreateJoinTokenOut) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreateJoinTokenOut) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *CreateJoinTokenOut) GetExpiresAt() int64 {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return 0
+}
+
+// RateLimit is limit of user which applies on every node and takes precedence over limits from config
+type RateLimit struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	User  string                 `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	// rate is amount of requests per second, requests are not limited if it is zero
+	Rate          float64 `protobuf:"fixed64,2,opt,name=rate,proto3" json:"rate,omitempty"`
+	Burst         int32   `protobuf:"varint,3,opt,name=burst,proto3" json:"burst,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RateLimit) Reset() {
+	*x = RateLimit{}
+	mi := &file_admin_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RateLimit) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RateLimit) ProtoMessage() {}
+
+func (x *RateLimit) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RateLimit.ProtoReflect.Descriptor instead.
+func (*RateLimit) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RateLimit) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+func (x *RateLimit) GetRate() float64 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *RateLimit) GetBurst() int32 {
+	if x != nil {
+		return x.Burst
+	}
+	return 0
+}
+
+type SetRateLimitIn struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         *RateLimit             `protobuf:"bytes,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRateLimitIn) Reset() {
+	*x = SetRateLimitIn{}
+	mi := &file_admin_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRateLimitIn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRateLimitIn) ProtoMessage() {}
+
+func (x *SetRateLimitIn) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRateLimitIn.ProtoReflect.Descriptor instead.
+func (*SetRateLimitIn) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SetRateLimitIn) GetLimit() *RateLimit {
+	if x != nil {
+		return x.Limit
+	}
+	return nil
+}
+
+type SetRateLimitOut struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetRateLimitOut) Reset() {
+	*x = SetRateLimitOut{}
+	mi := &file_admin_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetRateLimitOut) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetRateLimitOut) ProtoMessage() {}
+
+func (x *SetRateLimitOut) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetRateLimitOut.ProtoReflect.Descriptor instead.
+func (*SetRateLimitOut) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{13}
+}
+
+type DeleteRateLimitIn struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          string                 `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRateLimitIn) Reset() {
+	*x = DeleteRateLimitIn{}
+	mi := &file_admin_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRateLimitIn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRateLimitIn) ProtoMessage() {}
+
+func (x *DeleteRateLimitIn) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRateLimitIn.ProtoReflect.Descriptor instead.
+func (*DeleteRateLimitIn) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DeleteRateLimitIn) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+type DeleteRateLimitOut struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteRateLimitOut) Reset() {
+	*x = DeleteRateLimitOut{}
+	mi := &file_admin_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRateLimitOut) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRateLimitOut) ProtoMessage() {}
+
+func (x *DeleteRateLimitOut) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRateLimitOut.ProtoReflect.Descriptor instead.
+func (*DeleteRateLimitOut) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{15}
+}
+
+type ListRateLimitsIn struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRateLimitsIn) Reset() {
+	*x = ListRateLimitsIn{}
+	mi := &file_admin_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRateLimitsIn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRateLimitsIn) ProtoMessage() {}
+
+func (x *ListRateLimitsIn) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRateLimitsIn.ProtoReflect.Descriptor instead.
+func (*ListRateLimitsIn) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{16}
+}
+
+type ListRateLimitsOut struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limits        []*RateLimit           `protobuf:"bytes,1,rep,name=limits,proto3" json:"limits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRateLimitsOut) Reset() {
+	*x = ListRateLimitsOut{}
+	mi := &file_admin_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRateLimitsOut) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRateLimitsOut) ProtoMessage() {}
+
+func (x *ListRateLimitsOut) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRateLimitsOut.ProtoReflect.Descriptor instead.
+func (*ListRateLimitsOut) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListRateLimitsOut) GetLimits() []*RateLimit {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+type SetLogLevelIn struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// component is name of logger component like raft.FSM, global level is changed if it is empty
+	Component string `protobuf:"bytes,1,opt,name=component,proto3" json:"component,omitempty"`
+	// level is one of trace, debug, info, warn, error, level of component is reset to global one if it is empty
+	Level         string `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLogLevelIn) Reset() {
+	*x = SetLogLevelIn{}
+	mi := &file_admin_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLogLevelIn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLogLevelIn) ProtoMessage() {}
+
+func (x *SetLogLevelIn) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLogLevelIn.ProtoReflect.Descriptor instead.
+func (*SetLogLevelIn) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *SetLogLevelIn) GetComponent() string {
+	if x != nil {
+		return x.Component
+	}
+	return ""
+}
+
+func (x *SetLogLevelIn) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+type SetLogLevelOut struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SetLogLevelOut) Reset() {
+	*x = SetLogLevelOut{}
+	mi := &file_admin_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetLogLevelOut) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetLogLevelOut) ProtoMessage() {}
+
+func (x *SetLogLevelOut) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetLogLevelOut.ProtoReflect.Descriptor instead.
+func (*SetLogLevelOut) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{19}
+}
+
+type ListLogLevelsIn struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLogLevelsIn) Reset() {
+	*x = ListLogLevelsIn{}
+	mi := &file_admin_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLogLevelsIn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLogLevelsIn) ProtoMessage() {}
+
+func (x *ListLogLevelsIn) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLogLevelsIn.ProtoReflect.Descriptor instead.
+func (*ListLogLevelsIn) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{20}
+}
+
+type ComponentLevel struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Component     string                 `protobuf:"bytes,1,opt,name=component,proto3" json:"component,omitempty"`
+	Level         string                 `protobuf:"bytes,2,opt,name=level,proto3" json:"level,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ComponentLevel) Reset() {
+	*x = ComponentLevel{}
+	mi := &file_admin_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ComponentLevel) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ComponentLevel) ProtoMessage() {}
+
+func (x *ComponentLevel) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ComponentLevel.ProtoReflect.Descriptor instead.
+func (*ComponentLevel) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ComponentLevel) GetComponent() string {
+	if x != nil {
+		return x.Component
+	}
+	return ""
+}
+
+func (x *ComponentLevel) GetLevel() string {
+	if x != nil {
+		return x.Level
+	}
+	return ""
+}
+
+type ListLogLevelsOut struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Global        string                 `protobuf:"bytes,1,opt,name=global,proto3" json:"global,omitempty"`
+	Components    []*ComponentLevel      `protobuf:"bytes,2,rep,name=components,proto3" json:"components,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListLogLevelsOut) Reset() {
+	*x = ListLogLevelsOut{}
+	mi := &file_admin_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListLogLevelsOut) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListLogLevelsOut) ProtoMessage() {}
+
+func (x *ListLogLevelsOut) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListLogLevelsOut.ProtoReflect.Descriptor instead.
+func (*ListLogLevelsOut) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *ListLogLevelsOut) GetGlobal() string {
+	if x != nil {
+		return x.Global
+	}
+	return ""
+}
+
+func (x *ListLogLevelsOut) GetComponents() []*ComponentLevel {
+	if x != nil {
+		return x.Components
+	}
+	return nil
+}
+
+type WatchEventsIn struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchEventsIn) Reset() {
+	*x = WatchEventsIn{}
+	mi := &file_admin_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchEventsIn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchEventsIn) ProtoMessage() {}
+
+func (x *WatchEventsIn) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchEventsIn.ProtoReflect.Descriptor instead.
+func (*WatchEventsIn) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{23}
+}
+
+// ClusterEvent is one of leader_changed, state_changed, peer_added, peer_removed, heartbeat_failed,
+// heartbeat_resumed, snapshot_created and snapshot_restored, peer and heartbeat events are emitted only by the leader
+type ClusterEvent struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  string                 `protobuf:"bytes,1,opt,name=type,proto3" json:"type,omitempty"`
+	// time is unix time in milliseconds
+	Time int64 `protobuf:"varint,2,opt,name=time,proto3" json:"time,omitempty"`
+	// node_id is id of the node which emitted event
+	NodeId string `protobuf:"bytes,3,opt,name=node_id,json=nodeId,proto3" json:"node_id,omitempty"`
+	Term   uint64 `protobuf:"varint,4,opt,name=term,proto3" json:"term,omitempty"`
+	// state is new raft state of the node for state_changed
+	State string `protobuf:"bytes,5,opt,name=state,proto3" json:"state,omitempty"`
+	// server_id and server_address are of the new leader for leader_changed and of the peer for peer and heartbeat events
+	ServerId      string `protobuf:"bytes,6,opt,name=server_id,json=serverId,proto3" json:"server_id,omitempty"`
+	ServerAddress string `protobuf:"bytes,7,opt,name=server_address,json=serverAddress,proto3" json:"server_address,omitempty"`
+	// last_contact is unix time in milliseconds of the last successful heartbeat for heartbeat_failed
+	LastContact   int64 `protobuf:"varint,8,opt,name=last_contact,json=lastContact,proto3" json:"last_contact,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ClusterEvent) Reset() {
+	*x = ClusterEvent{}
+	mi := &file_admin_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ClusterEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ClusterEvent) ProtoMessage() {}
+
+func (x *ClusterEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_admin_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ClusterEvent.ProtoReflect.Descriptor instead.
+func (*ClusterEvent) Descriptor() ([]byte, []int) {
+	return file_admin_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ClusterEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *ClusterEvent) GetTime() int64 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *ClusterEvent) GetNodeId() string {
+	if x != nil {
+		return x.NodeId
+	}
+	return ""
+}
+
+func (x *ClusterEvent) GetTerm() uint64 {
+	if x != nil {
+		return x.Term
+	}
+	return 0
+}
+
+func (x *ClusterEvent) GetState() string {
+	if x != nil {
+		return x.State
+	}
+	return ""
+}
+
+func (x *ClusterEvent) GetServerId() string {
+	if x != nil {
+		return x.ServerId
+	}
+	return ""
+}
+
+func (x *ClusterEvent) GetServerAddress() string {
+	if x != nil {
+		return x.ServerAddress
+	}
+	return ""
+}
+
+func (x *ClusterEvent) GetLastContact() int64 {
+	if x != nil {
+		return x.LastContact
+	}
+	return 0
+}
+
+var File_admin_proto protoreflect.FileDescriptor
+
+const file_admin_proto_rawDesc = "" +
+	"\n" +
+	"\vadmin.proto\x12\akvstore\"T\n" +
+	"\fCreateUserIn\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\x12\x14\n" +
+	"\x05roles\x18\x03 \x03(\tR\x05roles\"\x0f\n" +
+	"\rCreateUserOut\"\"\n" +
+	"\fDeleteUserIn\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"\x0f\n" +
+	"\rDeleteUserOut\"B\n" +
+	"\x10ChangePasswordIn\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\bpassword\x18\x02 \x01(\tR\bpassword\"\x13\n" +
+	"\x11ChangePasswordOut\"\r\n" +
+	"\vListUsersIn\"7\n" +
+	"\fListUsersOut\x12'\n" +
+	"\x05users\x18\x01 \x03(\v2\x11.kvstore.UserInfoR\x05users\"4\n" +
+	"\bUserInfo\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05roles\x18\x02 \x03(\tR\x05roles\"4\n" +
+	"\x11CreateJoinTokenIn\x12\x1f\n" +
+	"\vttl_seconds\x18\x01 \x01(\x03R\n" +
+	"ttlSeconds\"I\n" +
+	"\x12CreateJoinTokenOut\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1d\n" +
+	"\n" +
+	"expires_at\x18\x02 \x01(\x03R\texpiresAt\"I\n" +
+	"\tRateLimit\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\tR\x04user\x12\x12\n" +
+	"\x04rate\x18\x02 \x01(\x01R\x04rate\x12\x14\n" +
+	"\x05burst\x18\x03 \x01(\x05R\x05burst\":\n" +
+	"\x0eSetRateLimitIn\x12(\n" +
+	"\x05limit\x18\x01 \x01(\v2\x12.kvstore.RateLimitR\x05limit\"\x11\n" +
+	"\x0fSetRateLimitOut\"'\n" +
+	"\x11DeleteRateLimitIn\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\tR\x04user\"\x14\n" +
+	"\x12DeleteRateLimitOut\"\x12\n" +
+	"\x10ListRateLimitsIn\"?\n" +
+	"\x11ListRateLimitsOut\x12*\n" +
+	"\x06limits\x18\x01 \x03(\v2\x12.kvstore.RateLimitR\x06limits\"C\n" +
+	"\rSetLogLevelIn\x12\x1c\n" +
+	"\tcomponent\x18\x01 \x01(\tR\tcomponent\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\tR\x05level\"\x10\n" +
+	"\x0eSetLogLevelOut\"\x11\n" +
+	"\x0fListLogLevelsIn\"D\n" +
+	"\x0eComponentLevel\x12\x1c\n" +
+	"\tcomponent\x18\x01 \x01(\tR\tcomponent\x12\x14\n" +
+	"\x05level\x18\x02 \x01(\tR\x05level\"c\n" +
+	"\x10ListLogLevelsOut\x12\x16\n" +
+	"\x06global\x18\x01 \x01(\tR\x06global\x127\n" +
+	"\n" +
+	"components\x18\x02 \x03(\v2\x17.kvstore.ComponentLevelR\n" +
+	"components\"\x0f\n" +
+	"\rWatchEventsIn\"\xe0\x01\n" +
+	"\fClusterEvent\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\tR\x04type\x12\x12\n" +
+	"\x04time\x18\x02 \x01(\x03R\x04time\x12\x17\n" +
+	"\anode_id\x18\x03 \x01(\tR\x06nodeId\x12\x12\n" +
+	"\x04term\x18\x04 \x01(\x04R\x04term\x12\x14\n" +
+	"\x05state\x18\x05 \x01(\tR\x05state\x12\x1b\n" +
+	"\tserver_id\x18\x06 \x01(\tR\bserverId\x12%\n" +
+	"\x0eserver_address\x18\a \x01(\tR\rserverAddress\x12!\n" +
+	"\flast_contact\x18\b \x01(\x03R\vlastContact2\xee\x05\n" +
+	"\x05Admin\x12;\n" +
+	"\n" +
+	"CreateUser\x12\x15.kvstore.CreateUserIn\x1a\x16.kvstore.CreateUserOut\x12;\n" +
+	"\n" +
+	"DeleteUser\x12\x15.kvstore.DeleteUserIn\x1a\x16.kvstore.DeleteUserOut\x12G\n" +
+	"\x0eChangePassword\x12\x19.kvstore.ChangePasswordIn\x1a\x1a.kvstore.ChangePasswordOut\x128\n" +
+	"\tListUsers\x12\x14.kvstore.ListUsersIn\x1a\x15.kvstore.ListUsersOut\x12J\n" +
+	"\x0fCreateJoinToken\x12\x1a.kvstore.CreateJoinTokenIn\x1a\x1b.kvstore.CreateJoinTokenOut\x12A\n" +
+	"\fSetRateLimit\x12\x17.kvstore.SetRateLimitIn\x1a\x18.kvstore.SetRateLimitOut\x12J\n" +
+	"\x0fDeleteRateLimit\x12\x1a.kvstore.DeleteRateLimitIn\x1a\x1b.kvstore.DeleteRateLimitOut\x12G\n" +
+	"\x0eListRateLimits\x12\x19.kvstore.ListRateLimitsIn\x1a\x1a.kvstore.ListRateLimitsOut\x12>\n" +
+	"\vSetLogLevel\x12\x16.kvstore.SetLogLevelIn\x1a\x17.kvstore.SetLogLevelOut\x12D\n" +
+	"\rListLogLevels\x12\x18.kvstore.ListLogLevelsIn\x1a\x19.kvstore.ListLogLevelsOut\x12>\n" +
+	"\vWatchEvents\x12\x16.kvstore.WatchEventsIn\x1a\x15.kvstore.ClusterEvent0\x01B\x15Z\x13kvstore/gen/go;kvpbb\x06proto3"
+
+var (
+	file_admin_proto_rawDescOnce sync.Once
+	file_admin_proto_rawDescData []byte
+)
+
+func file_admin_proto_rawDescGZIP() []byte {
+	file_admin_proto_rawDescOnce.Do(func() {
+		file_admin_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_admin_proto_rawDesc), len(file_admin_proto_rawDesc)))
+	})
+	return file_admin_proto_rawDescData
+}
+
+var file_admin_proto_msgTypes = make([]protoimpl.MessageInfo, 25)
+var file_admin_proto_goTypes = []any{
+	(*CreateUserIn)(nil),       // 0: kvstore.CreateUserIn
+	(*CreateUserOut)(nil),      // 1: kvstore.CreateUserOut
+	(*DeleteUserIn)(nil),       // 2: kvstore.DeleteUserIn
+	(*DeleteUserOut)(nil),      // 3: kvstore.DeleteUserOut
+	(*ChangePasswordIn)(nil),   // 4: kvstore.ChangePasswordIn
+	(*ChangePasswordOut)(nil),  // 5: kvstore.ChangePasswordOut
+	(*ListUsersIn)(nil),        // 6: kvstore.ListUsersIn
+	(*ListUsersOut)(nil),       // 7: kvstore.ListUsersOut
+	(*UserInfo)(nil),           // 8: kvstore.UserInfo
+	(*CreateJoinTokenIn)(nil),  // 9: kvstore.CreateJoinTokenIn
+	(*CreateJoinTokenOut)(nil), // 10: kvstore.CreateJoinTokenOut
+	(*RateLimit)(nil),          // 11: kvstore.RateLimit
+	(*SetRateLimitIn)(nil),     // 12: kvstore.SetRateLimitIn
+	(*SetRateLimitOut)(nil),    // 13: kvstore.SetRateLimitOut
+	(*DeleteRateLimitIn)(nil),  // 14: kvstore.DeleteRateLimitIn
+	(*DeleteRateLimitOut)(nil), // 15: kvstore.DeleteRateLimitOut
+	(*ListRateLimitsIn)(nil),   // 16: kvstore.ListRateLimitsIn
+	(*ListRateLimitsOut)(nil),  // 17: kvstore.ListRateLimitsOut
+	(*SetLogLevelIn)(nil),      // 18: kvstore.SetLogLevelIn
+	(*SetLogLevelOut)(nil),     // 19: kvstore.SetLogLevelOut
+	(*ListLogLevelsIn)(nil),    // 20: kvstore.ListLogLevelsIn
+	(*ComponentLevel)(nil),     // 21: kvstore.ComponentLevel
+	(*ListLogLevelsOut)(nil),   // 22: kvstore.ListLogLevelsOut
+	(*WatchEventsIn)(nil),      // 23: kvstore.WatchEventsIn
+	(*ClusterEvent)(nil),       // 24: kvstore.ClusterEvent
+}
+var file_admin_proto_depIdxs = []int32{
+	8,  // 0: kvstore.ListUsersOut.users:type_name -> kvstore.UserInfo
+	11, // 1: kvstore.SetRateLimitIn.limit:type_name -> kvstore.RateLimit
+	11, // 2: kvstore.ListRateLimitsOut.limits:type_name -> kvstore.RateLimit
+	21, // 3: kvstore.ListLogLevelsOut.components:type_name -> kvstore.ComponentLevel
+	0,  // 4: kvstore.Admin.CreateUser:input_type -> kvstore.CreateUserIn
+	2,  // 5: kvstore.Admin.DeleteUser:input_type -> kvstore.DeleteUserIn
+	4,  // 6: kvstore.Admin.ChangePassword:input_type -> kvstore.ChangePasswordIn
+	6,  // 7: kvstore.Admin.ListUsers:input_type -> kvstore.ListUsersIn
+	9,  // 8: kvstore.Admin.CreateJoinToken:input_type -> kvstore.CreateJoinTokenIn
+	12, // 9: kvstore.Admin.SetRateLimit:input_type -> kvstore.SetRateLimitIn
+	14, // 10: kvstore.Admin.DeleteRateLimit:input_type -> kvstore.DeleteRateLimitIn
+	16, // 11: kvstore.Admin.ListRateLimits:input_type -> kvstore.ListRateLimitsIn
+	18, // 12: kvstore.Admin.SetLogLevel:input_type -> kvstore.SetLogLevelIn
+	20, // 13: kvstore.Admin.ListLogLevels:input_type -> kvstore.ListLogLevelsIn
+	23, // 14: kvstore.Admin.WatchEvents:input_type -> kvstore.WatchEventsIn
+	1,  // 15: kvstore.Admin.CreateUser:output_type -> kvstore.CreateUserOut
+	3,  // 16: kvstore.Admin.DeleteUser:output_type -> kvstore.DeleteUserOut
+	5,  // 17: kvstore.Admin.ChangePassword:output_type -> kvstore.ChangePasswordOut
+	7,  // 18: kvstore.Admin.ListUsers:output_type -> kvstore.ListUsersOut
+	10, // 19: kvstore.Admin.CreateJoinToken:output_type -> kvstore.CreateJoinTokenOut
+	13, // 20: kvstore.Admin.SetRateLimit:output_type -> kvstore.SetRateLimitOut
+	15, // 21: kvstore.Admin.DeleteRateLimit:output_type -> kvstore.DeleteRateLimitOut
+	17, // 22: kvstore.Admin.ListRateLimits:output_type -> kvstore.ListRateLimitsOut
+	19, // 23: kvstore.Admin.SetLogLevel:output_type -> kvstore.SetLogLevelOut
+	22, // 24: kvstore.Admin.ListLogLevels:output_type -> kvstore.ListLogLevelsOut
+	24, // 25: kvstore.Admin.WatchEvents:output_type -> kvstore.ClusterEvent
+	15, // [15:26] is the sub-list for method output_type
+	4,  // [4:15] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_admin_proto_init() }
+func file_admin_proto_init() {
+	if File_admin_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_admin_proto_rawDesc), len(file_admin_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   25,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_admin_proto_goTypes,
+		DependencyIndexes: file_admin_proto_depIdxs,
+		MessageInfos:      file_admin_proto_msgTypes,
+	}.Build()
+	File_admin_proto = out.File
+	file_admin_proto_goTypes = nil
+	file_admin_proto_depIdxs = nil
+}
@@ -0,0 +1,196 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.21.12
+// source: transport.proto
+
+package kvpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type RaftMessage struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payload       []byte                 `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RaftMessage) Reset() {
+	*x = RaftMessage{}
+	mi := &file_transport_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RaftMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RaftMessage) ProtoMessage() {}
+
+func (x *RaftMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RaftMessage.ProtoReflect.Descriptor instead.
+func (*RaftMessage) Descriptor() ([]byte, []int) {
+	return file_transport_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *RaftMessage) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+// SnapshotChunk is a part of snapshot, the first chunk carries install snapshot request
+type SnapshotChunk struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Request       []byte                 `protobuf:"bytes,1,opt,name=request,proto3" json:"request,omitempty"`
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data,proto3" json:"data,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SnapshotChunk) Reset() {
+	*x = SnapshotChunk{}
+	mi := &file_transport_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SnapshotChunk) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SnapshotChunk) ProtoMessage() {}
+
+func (x *SnapshotChunk) ProtoReflect() protoreflect.Message {
+	mi := &file_transport_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SnapshotChunk.ProtoReflect.Descriptor instead.
+func (*SnapshotChunk) Descriptor() ([]byte, []int) {
+	return file_transport_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *SnapshotChunk) GetRequest() []byte {
+	if x != nil {
+		return x.Request
+	}
+	return nil
+}
+
+func (x *SnapshotChunk) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+var File_transport_proto protoreflect.FileDescriptor
+
+const file_transport_proto_rawDesc = "" +
+	"\n" +
+	"\x0ftransport.proto\x12\akvstore\"'\n" +
+	"\vRaftMessage\x12\x18\n" +
+	"\apayload\x18\x01 \x01(\fR\apayload\"=\n" +
+	"\rSnapshotChunk\x12\x18\n" +
+	"\arequest\x18\x01 \x01(\fR\arequest\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data2\xc2\x02\n" +
+	"\rRaftTransport\x12;\n" +
+	"\rAppendEntries\x12\x14.kvstore.RaftMessage\x1a\x14.kvstore.RaftMessage\x129\n" +
+	"\vRequestVote\x12\x14.kvstore.RaftMessage\x1a\x14.kvstore.RaftMessage\x12<\n" +
+	"\x0eRequestPreVote\x12\x14.kvstore.RaftMessage\x1a\x14.kvstore.RaftMessage\x128\n" +
+	"\n" +
+	"TimeoutNow\x12\x14.kvstore.RaftMessage\x1a\x14.kvstore.RaftMessage\x12A\n" +
+	"\x0fInstallSnapshot\x12\x16.kvstore.SnapshotChunk\x1a\x14.kvstore.RaftMessage(\x01B\x15Z\x13kvstore/gen/go;kvpbb\x06proto3"
+
+var (
+	file_transport_proto_rawDescOnce sync.Once
+	file_transport_proto_rawDescData []byte
+)
+
+func file_transport_proto_rawDescGZIP() []byte {
+	file_transport_proto_rawDescOnce.Do(func() {
+		file_transport_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_transport_proto_rawDesc), len(file_transport_proto_rawDesc)))
+	})
+	return file_transport_proto_rawDescData
+}
+
+var file_transport_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_transport_proto_goTypes = []any{
+	(*RaftMessage)(nil),   // 0: kvstore.RaftMessage
+	(*SnapshotChunk)(nil), // 1: kvstore.SnapshotChunk
+}
+var file_transport_proto_depIdxs = []int32{
+	0, // 0: kvstore.RaftTransport.AppendEntries:input_type -> kvstore.RaftMessage
+	0, // 1: kvstore.RaftTransport.RequestVote:input_type -> kvstore.RaftMessage
+	0, // 2: kvstore.RaftTransport.RequestPreVote:input_type -> kvstore.RaftMessage
+	0, // 3: kvstore.RaftTransport.TimeoutNow:input_type -> kvstore.RaftMessage
+	1, // 4: kvstore.RaftTransport.InstallSnapshot:input_type -> kvstore.SnapshotChunk
+	0, // 5: kvstore.RaftTransport.AppendEntries:output_type -> kvstore.RaftMessage
+	0, // 6: kvstore.RaftTransport.RequestVote:output_type -> kvstore.RaftMessage
+	0, // 7: kvstore.RaftTransport.RequestPreVote:output_type -> kvstore.RaftMessage
+	0, // 8: kvstore.RaftTransport.TimeoutNow:output_type -> kvstore.RaftMessage
+	0, // 9: kvstore.RaftTransport.InstallSnapshot:output_type -> kvstore.RaftMessage
+	5, // [5:10] is the sub-list for method output_type
+	0, // [0:5] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_transport_proto_init() }
+func file_transport_proto_init() {
+	if File_transport_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_transport_proto_rawDesc), len(file_transport_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_transport_proto_goTypes,
+		DependencyIndexes: file_transport_proto_depIdxs,
+		MessageInfos:      file_transport_proto_msgTypes,
+	}.Build()
+	File_transport_proto = out.File
+	file_transport_proto_goTypes = nil
+	file_transport_proto_depIdxs = nil
+}
@@ -0,0 +1,271 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v3.21.12
+// source: usage.proto
+
+package kvpb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type GetUsageIn struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user is the caller if it is not provided, usage of other users requires read permission on cluster
+	User          string `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageIn) Reset() {
+	*x = GetUsageIn{}
+	mi := &file_usage_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageIn) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageIn) ProtoMessage() {}
+
+func (x *GetUsageIn) ProtoReflect() protoreflect.Message {
+	mi := &file_usage_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageIn.ProtoReflect.Descriptor instead.
+func (*GetUsageIn) Descriptor() ([]byte, []int) {
+	return file_usage_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *GetUsageIn) GetUser() string {
+	if x != nil {
+		return x.User
+	}
+	return ""
+}
+
+type GetUsageOut struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	User          *UsageEntry            `protobuf:"bytes,1,opt,name=user,proto3" json:"user,omitempty"`
+	Prefixes      []*UsageEntry          `protobuf:"bytes,2,rep,name=prefixes,proto3" json:"prefixes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageOut) Reset() {
+	*x = GetUsageOut{}
+	mi := &file_usage_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageOut) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageOut) ProtoMessage() {}
+
+func (x *GetUsageOut) ProtoReflect() protoreflect.Message {
+	mi := &file_usage_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageOut.ProtoReflect.Descriptor instead.
+func (*GetUsageOut) Descriptor() ([]byte, []int) {
+	return file_usage_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *GetUsageOut) GetUser() *UsageEntry {
+	if x != nil {
+		return x.User
+	}
+	return nil
+}
+
+func (x *GetUsageOut) GetPrefixes() []*UsageEntry {
+	if x != nil {
+		return x.Prefixes
+	}
+	return nil
+}
+
+// UsageEntry is consumption of user or key prefix, zero limits mean there is no limit
+type UsageEntry struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Keys          int64                  `protobuf:"varint,2,opt,name=keys,proto3" json:"keys,omitempty"`
+	Bytes         int64                  `protobuf:"varint,3,opt,name=bytes,proto3" json:"bytes,omitempty"`
+	MaxKeys       int64                  `protobuf:"varint,4,opt,name=max_keys,json=maxKeys,proto3" json:"max_keys,omitempty"`
+	MaxBytes      int64                  `protobuf:"varint,5,opt,name=max_bytes,json=maxBytes,proto3" json:"max_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UsageEntry) Reset() {
+	*x = UsageEntry{}
+	mi := &file_usage_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UsageEntry) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UsageEntry) ProtoMessage() {}
+
+func (x *UsageEntry) ProtoReflect() protoreflect.Message {
+	mi := &file_usage_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UsageEntry.ProtoReflect.Descriptor instead.
+func (*UsageEntry) Descriptor() ([]byte, []int) {
+	return file_usage_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *UsageEntry) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UsageEntry) GetKeys() int64 {
+	if x != nil {
+		return x.Keys
+	}
+	return 0
+}
+
+func (x *UsageEntry) GetBytes() int64 {
+	if x != nil {
+		return x.Bytes
+	}
+	return 0
+}
+
+func (x *UsageEntry) GetMaxKeys() int64 {
+	if x != nil {
+		return x.MaxKeys
+	}
+	return 0
+}
+
+func (x *UsageEntry) GetMaxBytes() int64 {
+	if x != nil {
+		return x.MaxBytes
+	}
+	return 0
+}
+
+var File_usage_proto protoreflect.FileDescriptor
+
+const file_usage_proto_rawDesc = "" +
+	"\n" +
+	"\vusage.proto\x12\akvstore\" \n" +
+	"\n" +
+	"GetUsageIn\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\tR\x04user\"g\n" +
+	"\vGetUsageOut\x12'\n" +
+	"\x04user\x18\x01 \x01(\v2\x13.kvstore.UsageEntryR\x04user\x12/\n" +
+	"\bprefixes\x18\x02 \x03(\v2\x13.kvstore.UsageEntryR\bprefixes\"\x82\x01\n" +
+	"\n" +
+	"UsageEntry\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x12\n" +
+	"\x04keys\x18\x02 \x01(\x03R\x04keys\x12\x14\n" +
+	"\x05bytes\x18\x03 \x01(\x03R\x05bytes\x12\x19\n" +
+	"\bmax_keys\x18\x04 \x01(\x03R\amaxKeys\x12\x1b\n" +
+	"\tmax_bytes\x18\x05 \x01(\x03R\bmaxBytes2>\n" +
+	"\x05Usage\x125\n" +
+	"\bGetUsage\x12\x13.kvstore.GetUsageIn\x1a\x14.kvstore.GetUsageOutB\x15Z\x13kvstore/gen/go;kvpbb\x06proto3"
+
+var (
+	file_usage_proto_rawDescOnce sync.Once
+	file_usage_proto_rawDescData []byte
+)
+
+func file_usage_proto_rawDescGZIP() []byte {
+	file_usage_proto_rawDescOnce.Do(func() {
+		file_usage_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_usage_proto_rawDesc), len(file_usage_proto_rawDesc)))
+	})
+	return file_usage_proto_rawDescData
+}
+
+var file_usage_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_usage_proto_goTypes = []any{
+	(*GetUsageIn)(nil),  // 0: kvstore.GetUsageIn
+	(*GetUsageOut)(nil), // 1: kvstore.GetUsageOut
+	(*UsageEntry)(nil),  // 2: kvstore.UsageEntry
+}
+var file_usage_proto_depIdxs = []int32{
+	2, // 0: kvstore.GetUsageOut.user:type_name -> kvstore.UsageEntry
+	2, // 1: kvstore.GetUsageOut.prefixes:type_name -> kvstore.UsageEntry
+	0, // 2: kvstore.Usage.GetUsage:input_type -> kvstore.GetUsageIn
+	1, // 3: kvstore.Usage.GetUsage:output_type -> kvstore.GetUsageOut
+	3, // [3:4] is the sub-list for method output_type
+	2, // [2:3] is the sub-list for method input_type
+	2, // [2:2] is the sub-list for extension type_name
+	2, // [2:2] is the sub-list for extension extendee
+	0, // [0:2] is the sub-list for field type_name
+}
+
+func init() { file_usage_proto_init() }
+func file_usage_proto_init() {
+	if File_usage_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_usage_proto_rawDesc), len(file_usage_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_usage_proto_goTypes,
+		DependencyIndexes: file_usage_proto_depIdxs,
+		MessageInfos:      file_usage_proto_msgTypes,
+	}.Build()
+	File_usage_proto = out.File
+	file_usage_proto_goTypes = nil
+	file_usage_proto_depIdxs = nil
+}
@@ -0,0 +1,125 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v3.21.12
+// source: usage.proto
+
+package kvpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	Usage_GetUsage_FullMethodName = "/kvstore.Usage/GetUsage"
+)
+
+// UsageClient is the client API for Usage service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Usage reports consumption of storage quotas as seen by the node which serves the request
+type UsageClient interface {
+	GetUsage(ctx context.Context, in *GetUsageIn, opts ...grpc.CallOption) (*GetUsageOut, error)
+}
+
+type usageClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUsageClient(cc grpc.ClientConnInterface) UsageClient {
+	return &usageClient{cc}
+}
+
+func (c *usageClient) GetUsage(ctx context.Context, in *GetUsageIn, opts ...grpc.CallOption) (*GetUsageOut, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUsageOut)
+	err := c.cc.Invoke(ctx, Usage_GetUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UsageServer is the server API for Usage service.
+// All implementations must embed UnimplementedUsageServer
+// for forward compatibility.
+//
+// Usage reports consumption of storage quotas as seen by the node which serves the request
+type UsageServer interface {
+	GetUsage(context.Context, *GetUsageIn) (*GetUsageOut, error)
+	mustEmbedUnimplementedUsageServer()
+}
+
+// UnimplementedUsageServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedUsageServer struct{}
+
+func (UnimplementedUsageServer) GetUsage(context.Context, *GetUsageIn) (*GetUsageOut, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsage not implemented")
+}
+func (UnimplementedUsageServer) mustEmbedUnimplementedUsageServer() {}
+func (UnimplementedUsageServer) testEmbeddedByValue()               {}
+
+// UnsafeUsageServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UsageServer will
+// result in compilation errors.
+type UnsafeUsageServer interface {
+	mustEmbedUnimplementedUsageServer()
+}
+
+func RegisterUsageServer(s grpc.ServiceRegistrar, srv UsageServer) {
+	// If the following call pancis, it indicates UnimplementedUsageServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Usage_ServiceDesc, srv)
+}
+
+func _Usage_GetUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsageIn)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsageServer).GetUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Usage_GetUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsageServer).GetUsage(ctx, req.(*GetUsageIn))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Usage_ServiceDesc is the grpc.ServiceDesc for Usage service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Usage_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "kvstore.Usage",
+	HandlerType: (*UsageServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetUsage",
+			Handler:    _Usage_GetUsage_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "usage.proto",
+}
@@ -0,0 +1,264 @@
+package admin
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"kvstore/internal/auth"
+	"kvstore/internal/raft"
+	"kvstore/internal/sl"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/http/pprof"
+	"time"
+)
+
+const (
+	defaultAddress  = ":9090"
+	shutdownTimeout = 10 * time.Second
+	headerTimeout   = 10 * time.Second
+)
+
+type node interface {
+	Status() raft.Status
+	Ready() bool
+}
+
+type authenticator interface {
+	Authenticate(ctx context.Context, username, password string) (auth.Identity, error)
+}
+
+type policy interface {
+	Authorize(id auth.Identity, req auth.Request) error
+}
+
+type logLevels interface {
+	Set(component string, level slog.Level)
+	Reset(component string)
+	List() (slog.Level, map[string]slog.Level)
+}
+
+type Config struct {
+	Address string
+	// RequireAuth makes every endpoint except /ready require basic auth of user with cluster read permission,
+	// log levels can be changed only if it is enabled and only by user with cluster admin permission
+	RequireAuth bool
+	// TLS makes server use tls config of the node
+	TLS bool
+	// PProf exposes memory contents and command line of the process, so it can be enabled only with RequireAuth
+	PProf bool
+}
+
+// Server is http server for metrics, profiling, node status and log levels
+type Server struct {
+	logger   *slog.Logger
+	listener net.Listener
+	server   *http.Server
+}
+
+func New(logger *slog.Logger, node node, authenticator authenticator, policy policy, levels logLevels, tlsConfig *tls.Config, conf Config) (*Server, error) {
+	if logger == nil {
+		return nil, errors.New("logger required")
+	}
+
+	logger = logger.With(sl.Component("admin.Server"))
+
+	if node == nil {
+		return nil, errors.New("node required")
+	}
+	if levels == nil {
+		return nil, errors.New("levels required")
+	}
+	if conf.RequireAuth && authenticator == nil {
+		return nil, errors.New("authenticator required to require auth")
+	}
+	if conf.RequireAuth && policy == nil {
+		return nil, errors.New("policy required to require auth")
+	}
+	if conf.PProf && !conf.RequireAuth {
+		return nil, errors.New("auth must be required to enable pprof")
+	}
+	if conf.TLS && tlsConfig == nil {
+		return nil, errors.New("tls config required to serve tls")
+	}
+	if conf.Address == "" {
+		conf.Address = defaultAddress
+	}
+
+	s := &Server{logger: logger}
+
+	protect := func(handler http.Handler) http.Handler {
+		if !conf.RequireAuth {
+			return handler
+		}
+		return s.withAuth(authenticator, policy, auth.PermissionRead, handler)
+	}
+
+	logLevels := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "changing log level requires auth to be enabled", http.StatusForbidden)
+	}))
+	if conf.RequireAuth {
+		logLevels = s.withAuth(authenticator, policy, auth.PermissionAdmin, setLogLevelHandler(logger, levels))
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", protect(promhttp.Handler()))
+	mux.Handle("/status", protect(statusHandler(node)))
+	mux.Handle("/ready", readyHandler(node))
+	mux.Handle("GET /loglevel", protect(listLogLevelsHandler(levels)))
+	mux.Handle("PUT /loglevel", logLevels)
+	if conf.PProf {
+		mux.Handle("/debug/pprof/", protect(http.HandlerFunc(pprof.Index)))
+		mux.Handle("/debug/pprof/cmdline", protect(http.HandlerFunc(pprof.Cmdline)))
+		mux.Handle("/debug/pprof/profile", protect(http.HandlerFunc(pprof.Profile)))
+		mux.Handle("/debug/pprof/symbol", protect(http.HandlerFunc(pprof.Symbol)))
+		mux.Handle("/debug/pprof/trace", protect(http.HandlerFunc(pprof.Trace)))
+	}
+
+	listener, err := net.Listen("tcp", conf.Address)
+	if err != nil {
+		return nil, fmt.Errorf("failed create listener: %w", err)
+	}
+	if conf.TLS {
+		listener = tls.NewListener(listener, tlsConfig)
+	}
+
+	s.listener = listener
+	s.server = &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: headerTimeout,
+		ErrorLog:          slog.NewLogLogger(logger.Handler(), slog.LevelWarn),
+	}
+
+	logger.Debug("created successfully", sl.Conf(conf))
+
+	return s, nil
+}
+
+func (s *Server) Run() error {
+	s.logger.Info("starting server", slog.String("address", s.listener.Addr().String()))
+
+	if err := s.server.Serve(s.listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+func (s *Server) Shutdown() error {
+	s.logger.Info("shutting down")
+
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+
+	if err := s.server.Shutdown(ctx); err != nil {
+		return err
+	}
+
+	s.logger.Info("shut down gracefully")
+
+	return nil
+}
+
+func (s *Server) withAuth(authenticator authenticator, policy policy, permission auth.Permission, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Basic realm="kvstore"`)
+			http.Error(w, "credentials required", http.StatusUnauthorized)
+			return
+		}
+
+		id, err := authenticator.Authenticate(r.Context(), username, password)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Basic realm="kvstore"`)
+			http.Error(w, "invalid credentials", http.StatusUnauthorized)
+			return
+		}
+
+		err = policy.Authorize(id, auth.Request{Group: auth.GroupCluster, Permission: permission})
+		if err != nil {
+			s.logger.Warn("permission denied", slog.String("user", id.User), slog.String("path", r.URL.Path))
+			http.Error(w, "permission denied", http.StatusForbidden)
+			return
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+func statusHandler(node node) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(node.Status())
+	})
+}
+
+// readyHandler answers 200 if node is a member of cluster with a leader and 503 otherwise
+func readyHandler(node node) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		if !node.Ready() {
+			http.Error(w, "not ready", http.StatusServiceUnavailable)
+			return
+		}
+
+		_, _ = fmt.Fprintln(w, "ready")
+	})
+}
+
+type logLevelsOut struct {
+	Global     string            `json:"global"`
+	Components map[string]string `json:"components"`
+}
+
+func listLogLevelsHandler(levels logLevels) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		global, components := levels.List()
+
+		out := logLevelsOut{
+			Global:     sl.LevelName(global),
+			Components: make(map[string]string, len(components)),
+		}
+		for component, level := range components {
+			out.Components[component] = sl.LevelName(level)
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(out)
+	})
+}
+
+// setLogLevelHandler changes level of component from query, global level is changed if component is empty
+// and level of component is reset to global one if level is empty
+func setLogLevelHandler(logger *slog.Logger, levels logLevels) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		component := r.URL.Query().Get("component")
+		name := r.URL.Query().Get("level")
+
+		if name == "" {
+			if component == "" {
+				http.Error(w, "global level cannot be reset", http.StatusBadRequest)
+				return
+			}
+
+			levels.Reset(component)
+			logger.Info("log level reset", slog.String("logger", component))
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		level, err := sl.ParseLevel(name)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		levels.Set(component, level)
+		logger.Info("log level changed", slog.String("logger", component), slog.String("to", sl.LevelName(level)))
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
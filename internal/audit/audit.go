@@ -0,0 +1,152 @@
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"kvstore/internal/sl"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// ValuesOmit drops values from events
+	ValuesOmit = "omit"
+	// ValuesHash replaces values with their sha256, so equal values can be matched without revealing them
+	ValuesHash = "hash"
+	// ValuesPlain records values as they are
+	ValuesPlain = "plain"
+
+	defaultMaxSize    = 100 * 1024 * 1024
+	defaultMaxBackups = 5
+)
+
+// Event is a record of mutating or administrative request
+type Event struct {
+	Time   time.Time `json:"time"`
+	User   string    `json:"user,omitempty"`
+	Peer   string    `json:"peer,omitempty"`
+	Method string    `json:"method"`
+	Keys   []string  `json:"keys,omitempty"`
+	Value  string    `json:"value,omitempty"`
+	Result string    `json:"result"`
+	Error  string    `json:"error,omitempty"`
+	// Index is raft index of the last log applied by request
+	Index uint64 `json:"index,omitempty"`
+}
+
+type Config struct {
+	// File is path of audit log, audit is disabled if it is empty
+	File string
+	// MaxSize is size in bytes which file is rotated after
+	MaxSize    int64
+	MaxBackups int
+	// Values is one of omit, hash and plain
+	Values string
+}
+
+// Logger writes events as json lines to a rotating file separate from operational logs
+type Logger struct {
+	logger *slog.Logger
+	values string
+	mu     *sync.Mutex
+	out    io.WriteCloser
+}
+
+func New(logger *slog.Logger, conf Config) (*Logger, error) {
+	if logger == nil {
+		return nil, errors.New("logger required")
+	}
+
+	logger = logger.With(sl.Component("audit.Logger"))
+
+	if conf.MaxSize <= 0 {
+		conf.MaxSize = defaultMaxSize
+	}
+	if conf.MaxBackups <= 0 {
+		conf.MaxBackups = defaultMaxBackups
+	}
+
+	switch conf.Values {
+	case "":
+		conf.Values = ValuesOmit
+	case ValuesOmit, ValuesHash, ValuesPlain:
+	default:
+		return nil, fmt.Errorf("unknown values mode %q", conf.Values)
+	}
+
+	l := &Logger{
+		logger: logger,
+		values: conf.Values,
+		mu:     new(sync.Mutex),
+	}
+
+	if conf.File != "" {
+		out, err := openRotating(conf.File, conf.MaxSize, conf.MaxBackups)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open audit log: %w", err)
+		}
+		l.out = out
+	}
+
+	logger.Debug("created successfully", sl.Conf(conf))
+
+	return l, nil
+}
+
+func (l *Logger) Enabled() bool {
+	return l.out != nil
+}
+
+// Record writes event, failures are logged since request is already handled
+func (l *Logger) Record(event Event) {
+	if l.out == nil {
+		return
+	}
+
+	event.Value = l.redact(event.Value)
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		l.logger.Error("cannot marshal audit event", sl.Error(err))
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, err := l.out.Write(append(data, '\n')); err != nil {
+		l.logger.Error("cannot write audit event", sl.Error(err))
+	}
+}
+
+func (l *Logger) Close() error {
+	if l.out == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.out.Close()
+}
+
+func (l *Logger) redact(value string) string {
+	if value == "" {
+		return ""
+	}
+
+	switch l.values {
+	case ValuesPlain:
+		return value
+	case ValuesHash:
+		sum := sha256.Sum256([]byte(value))
+		return "sha256:" + hex.EncodeToString(sum[:])
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,70 @@
+package audit
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"sync/atomic"
+)
+
+type indexKey struct{}
+
+type actionsKey struct{}
+
+// Action is a change made while handling request besides the request itself,
+// e.g. removing stale cluster member when node joins
+type Action struct {
+	Method string
+	Keys   []string
+	Index  uint64
+	Err    error
+}
+
+type actions struct {
+	mu   *sync.Mutex
+	list []Action
+}
+
+// WithIndex prepares context to receive raft index of the log applied while handling request
+func WithIndex(ctx context.Context) context.Context {
+	return context.WithValue(ctx, indexKey{}, new(atomic.Uint64))
+}
+
+// SetIndex remembers raft index if context was prepared with WithIndex
+func SetIndex(ctx context.Context, index uint64) {
+	if holder, ok := ctx.Value(indexKey{}).(*atomic.Uint64); ok {
+		holder.Store(index)
+	}
+}
+
+func IndexFrom(ctx context.Context) uint64 {
+	if holder, ok := ctx.Value(indexKey{}).(*atomic.Uint64); ok {
+		return holder.Load()
+	}
+	return 0
+}
+
+// WithActions prepares context to receive actions made while handling request
+func WithActions(ctx context.Context) context.Context {
+	return context.WithValue(ctx, actionsKey{}, &actions{mu: new(sync.Mutex)})
+}
+
+// AddAction remembers action if context was prepared with WithActions
+func AddAction(ctx context.Context, action Action) {
+	if holder, ok := ctx.Value(actionsKey{}).(*actions); ok {
+		holder.mu.Lock()
+		defer holder.mu.Unlock()
+
+		holder.list = append(holder.list, action)
+	}
+}
+
+func ActionsFrom(ctx context.Context) []Action {
+	if holder, ok := ctx.Value(actionsKey{}).(*actions); ok {
+		holder.mu.Lock()
+		defer holder.mu.Unlock()
+
+		return slices.Clone(holder.list)
+	}
+	return nil
+}
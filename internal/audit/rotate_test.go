@@ -0,0 +1,112 @@
+package audit
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestRotatingFile(t *testing.T) {
+	tests := []struct {
+		name       string
+		existing   string
+		maxSize    int64
+		maxBackups int
+		writes     []string
+		// want are contents of file, file.1, file.2 and so on, missing files are empty
+		want []string
+	}{
+		{
+			name:       "no rotation under max size",
+			maxSize:    10,
+			maxBackups: 2,
+			writes:     []string{"aaa\n", "bbb\n"},
+			want:       []string{"aaa\nbbb\n"},
+		},
+		{
+			name:       "rotates before exceeding max size",
+			maxSize:    6,
+			maxBackups: 2,
+			writes:     []string{"aaa\n", "bbb\n", "ccc\n"},
+			want:       []string{"ccc\n", "bbb\n", "aaa\n"},
+		},
+		{
+			name:       "oldest backup is removed",
+			maxSize:    4,
+			maxBackups: 2,
+			writes:     []string{"aaa\n", "bbb\n", "ccc\n", "ddd\n"},
+			want:       []string{"ddd\n", "ccc\n", "bbb\n", ""},
+		},
+		{
+			name:       "write larger than max size is not split",
+			maxSize:    4,
+			maxBackups: 1,
+			writes:     []string{"aaaaaaaa\n", "bbb\n"},
+			want:       []string{"bbb\n", "aaaaaaaa\n"},
+		},
+		{
+			name:       "size of existing file is counted",
+			existing:   "old\n",
+			maxSize:    6,
+			maxBackups: 1,
+			writes:     []string{"new\n"},
+			want:       []string{"new\n", "old\n"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "audit.log")
+			if tt.existing != "" {
+				if err := os.WriteFile(path, []byte(tt.existing), 0o600); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			file, err := openRotating(path, tt.maxSize, tt.maxBackups)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for _, write := range tt.writes {
+				if _, err := file.Write([]byte(write)); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if err := file.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			got := []string{read(t, path)}
+			for i := 1; i < len(tt.want); i++ {
+				got = append(got, read(t, file.backup(i)))
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("files = %q, want %q", got, tt.want)
+			}
+
+			entries, err := os.ReadDir(filepath.Dir(path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) > tt.maxBackups+1 {
+				t.Fatalf("%d files are kept, want at most %d backups", len(entries), tt.maxBackups)
+			}
+		})
+	}
+}
+
+func read(t *testing.T, path string) string {
+	t.Helper()
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return ""
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return string(data)
+}
@@ -0,0 +1,196 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"crypto/x509"
+	"errors"
+	"golang.org/x/crypto/bcrypt"
+	"kvstore/internal/sl"
+	"log/slog"
+	"sync"
+)
+
+var ErrInvalidCredentials = errors.New("username or password mismatch")
+
+// verifiedCacheSize limits amount of remembered successful verifications,
+// bcrypt is slow on purpose, so it is not run for every request of the same client
+const verifiedCacheSize = 1024
+
+type usersReader interface {
+	Get(ctx context.Context, name string) (User, error)
+}
+
+type AuthenticatorConfig struct {
+	// Username and Password are credentials of built-in administrator
+	Username string
+	Password sl.Secret
+	// ClusterSecret authenticates other nodes of cluster
+	ClusterSecret sl.Secret
+	// NodeNames are common or DNS names of verified client certificates which belong to other nodes of cluster
+	NodeNames []string
+}
+
+// Authenticator checks credentials against built-in administrator and replicated user database
+type Authenticator struct {
+	logger       *slog.Logger
+	users        usersReader
+	rootUsername [sha256.Size]byte
+	rootPassword [sha256.Size]byte
+	rootRoles    []string
+	secret       [sha256.Size]byte
+	hasSecret    bool
+	nodeNames    map[string]struct{}
+	dummyHash    []byte
+	mu           *sync.Mutex
+	// cacheKey is random for every process, so keys of verified can not be
+	// checked against guessed passwords as fast as plain sha256
+	cacheKey []byte
+	verified map[[sha256.Size]byte][]byte
+}
+
+func NewAuthenticator(logger *slog.Logger, users usersReader, conf AuthenticatorConfig) (*Authenticator, error) {
+	if logger == nil {
+		return nil, errors.New("logger required")
+	}
+
+	logger = logger.With(sl.Component("auth.Authenticator"))
+
+	if users == nil {
+		return nil, errors.New("users required")
+	}
+	if conf.Username == "" {
+		return nil, errors.New("username required")
+	}
+	if conf.Password == "" {
+		return nil, errors.New("password required")
+	}
+
+	dummyHash, err := HashPassword("dummy password to compare unknown users with")
+	if err != nil {
+		return nil, err
+	}
+
+	cacheKey := make([]byte, sha256.Size)
+	if _, err := rand.Read(cacheKey); err != nil {
+		return nil, err
+	}
+
+	rootRoles := []string{RoleAdmin}
+	if conf.ClusterSecret == "" && len(conf.NodeNames) == 0 {
+		logger.Warn("neither cluster secret nor node certificates are configured, " +
+			"administrator credentials are accepted for cluster-internal RPCs")
+		rootRoles = append(rootRoles, RoleNode)
+	}
+
+	nodeNames := make(map[string]struct{}, len(conf.NodeNames))
+	for _, name := range conf.NodeNames {
+		nodeNames[name] = struct{}{}
+	}
+
+	logger.Debug("created successfully", sl.Conf(conf))
+
+	return &Authenticator{
+		logger:       logger,
+		users:        users,
+		rootUsername: sha256.Sum256([]byte(conf.Username)),
+		rootPassword: sha256.Sum256([]byte(conf.Password)),
+		rootRoles:    rootRoles,
+		secret:       sha256.Sum256([]byte(conf.ClusterSecret)),
+		hasSecret:    conf.ClusterSecret != "",
+		nodeNames:    nodeNames,
+		dummyHash:    dummyHash,
+		mu:           new(sync.Mutex),
+		cacheKey:     cacheKey,
+		verified:     make(map[[sha256.Size]byte][]byte),
+	}, nil
+}
+
+func (a *Authenticator) Authenticate(ctx context.Context, username, password string) (Identity, error) {
+	usernameSum := sha256.Sum256([]byte(username))
+	passwordSum := sha256.Sum256([]byte(password))
+
+	isRootUsername := subtle.ConstantTimeCompare(usernameSum[:], a.rootUsername[:])
+	isRootPassword := subtle.ConstantTimeCompare(passwordSum[:], a.rootPassword[:])
+	if isRootUsername&isRootPassword == 1 {
+		return Identity{User: username, Roles: a.rootRoles}, nil
+	}
+
+	user, err := a.users.Get(ctx, username)
+	if errors.Is(err, ErrNoUser) {
+		// spend the same time as for existing user to not reveal which users exist
+		_ = bcrypt.CompareHashAndPassword(a.dummyHash, []byte(password))
+		return Identity{}, ErrInvalidCredentials
+	}
+	if err != nil {
+		return Identity{}, err
+	}
+
+	if !a.verify(user, password) {
+		return Identity{}, ErrInvalidCredentials
+	}
+
+	return Identity{User: user.Name, Roles: user.Roles}, nil
+}
+
+// AuthenticateNode checks cluster secret presented by other node
+func (a *Authenticator) AuthenticateNode(secret string) (Identity, error) {
+	sum := sha256.Sum256([]byte(secret))
+	if !a.hasSecret || subtle.ConstantTimeCompare(sum[:], a.secret[:]) != 1 {
+		return Identity{}, ErrInvalidCredentials
+	}
+
+	return Identity{User: "node", Roles: []string{RoleNode}, Node: true}, nil
+}
+
+// AuthenticateCertificate recognizes other nodes by names in their verified client certificates
+func (a *Authenticator) AuthenticateCertificate(cert *x509.Certificate) (Identity, bool) {
+	for _, name := range append([]string{cert.Subject.CommonName}, cert.DNSNames...) {
+		if _, ok := a.nodeNames[name]; ok && name != "" {
+			return Identity{User: name, Roles: []string{RoleNode}, Node: true}, true
+		}
+	}
+
+	return Identity{}, false
+}
+
+func (a *Authenticator) verify(user User, password string) bool {
+	mac := hmac.New(sha256.New, a.cacheKey)
+	mac.Write([]byte(user.Name + "\x00" + password))
+
+	var key [sha256.Size]byte
+	mac.Sum(key[:0])
+
+	a.mu.Lock()
+	cached, ok := a.verified[key]
+	a.mu.Unlock()
+
+	if ok && subtle.ConstantTimeCompare(cached, user.PasswordHash) == 1 {
+		return true
+	}
+
+	if err := bcrypt.CompareHashAndPassword(user.PasswordHash, []byte(password)); err != nil {
+		return false
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if len(a.verified) >= verifiedCacheSize {
+		clear(a.verified)
+	}
+	a.verified[key] = user.PasswordHash
+
+	return true
+}
+
+func HashPassword(password string) ([]byte, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return nil, err
+	}
+	return hash, nil
+}
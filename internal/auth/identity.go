@@ -0,0 +1,43 @@
+package auth
+
+import (
+	"context"
+	"slices"
+	"strings"
+)
+
+// Identity is authenticated caller
+type Identity struct {
+	User  string
+	Roles []string
+	// Scope limits keys available to caller by their prefixes, every key is available if it is empty
+	Scope []string
+	// Node is set for other nodes of cluster authenticated with cluster secret or node certificate,
+	// unlike node role which is also held by administrator if neither is configured
+	Node bool
+}
+
+func (id Identity) HasRole(role string) bool {
+	return slices.Contains(id.Roles, role)
+}
+
+func (id Identity) InScope(key string) bool {
+	if len(id.Scope) == 0 {
+		return true
+	}
+
+	return slices.ContainsFunc(id.Scope, func(prefix string) bool {
+		return strings.HasPrefix(key, prefix)
+	})
+}
+
+type identityKey struct{}
+
+func WithIdentity(ctx context.Context, id Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, id)
+}
+
+func IdentityFrom(ctx context.Context) (Identity, bool) {
+	id, ok := ctx.Value(identityKey{}).(Identity)
+	return id, ok
+}
@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"maps"
+	"sync"
+	"time"
+)
+
+var (
+	ErrNoJoinToken      = errors.New("no such join token")
+	ErrJoinTokenExpired = errors.New("join token expired")
+)
+
+const joinTokenLength = 32
+
+// JoinToken allows one node to join to cluster once, only hash of the token is stored
+type JoinToken struct {
+	Hash      string    `json:"hash"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// NewJoinToken generates secret which is given to the joining node and token to store
+func NewJoinToken(ttl time.Duration) (string, JoinToken, error) {
+	raw := make([]byte, joinTokenLength)
+	if _, err := rand.Read(raw); err != nil {
+		return "", JoinToken{}, err
+	}
+
+	secret := base64.RawURLEncoding.EncodeToString(raw)
+
+	return secret, JoinToken{
+		Hash:      HashJoinToken(secret),
+		ExpiresAt: time.Now().Add(ttl),
+	}, nil
+}
+
+func HashJoinToken(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// JoinTokens is replicated via raft, so it is changed only by FSM and uses time of raft log instead of local clock
+type JoinTokens struct {
+	mu     *sync.Mutex
+	tokens map[string]JoinToken
+}
+
+func NewJoinTokens() *JoinTokens {
+	return &JoinTokens{
+		mu:     new(sync.Mutex),
+		tokens: make(map[string]JoinToken),
+	}
+}
+
+// Create stores token and forgets tokens which are expired at now
+func (t *JoinTokens) Create(_ context.Context, token JoinToken, now time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	maps.DeleteFunc(t.tokens, func(_ string, token JoinToken) bool {
+		return now.After(token.ExpiresAt)
+	})
+
+	t.tokens[token.Hash] = token
+
+	return nil
+}
+
+// Check returns the same error as Use would without removing token
+func (t *JoinTokens) Check(_ context.Context, hash string, now time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	token, ok := t.tokens[hash]
+	if !ok {
+		return ErrNoJoinToken
+	}
+	if now.After(token.ExpiresAt) {
+		return ErrJoinTokenExpired
+	}
+
+	return nil
+}
+
+// Use removes token, so it cannot be used twice
+func (t *JoinTokens) Use(_ context.Context, hash string, now time.Time) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	token, ok := t.tokens[hash]
+	if !ok {
+		return ErrNoJoinToken
+	}
+
+	delete(t.tokens, hash)
+
+	if now.After(token.ExpiresAt) {
+		return ErrJoinTokenExpired
+	}
+
+	return nil
+}
+
+func (t *JoinTokens) Snapshot(_ context.Context) (map[string]JoinToken, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return maps.Clone(t.tokens), nil
+}
+
+func (t *JoinTokens) Load(_ context.Context, tokens map[string]JoinToken) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if tokens == nil {
+		tokens = make(map[string]JoinToken)
+	}
+	t.tokens = tokens
+
+	return nil
+}
@@ -0,0 +1,188 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"kvstore/internal/sl"
+	"log/slog"
+	"slices"
+	"strings"
+)
+
+var ErrPermissionDenied = errors.New("permission denied")
+
+type Permission string
+
+const (
+	PermissionRead  Permission = "read"
+	PermissionWrite Permission = "write"
+	// PermissionAdmin implies read and write
+	PermissionAdmin Permission = "admin"
+)
+
+// Group is a group of RPCs permissions are granted on
+type Group string
+
+const (
+	// GroupData is reading and writing keys, its rules are limited by key prefixes
+	GroupData Group = "data"
+	// GroupCluster is managing users and cluster
+	GroupCluster Group = "cluster"
+	// GroupInternal is joining nodes and raft transport, it is allowed only to other nodes of cluster
+	GroupInternal Group = "internal"
+)
+
+type Rule struct {
+	Group Group
+	// Prefix limits rule of data group to keys with the prefix, rule is applied to every key if it is empty
+	Prefix      string
+	Permissions []Permission
+}
+
+type Role struct {
+	Name  string
+	Rules []Rule
+}
+
+type PolicyConfig struct {
+	Roles []Role
+}
+
+// Request is an action caller wants to perform
+type Request struct {
+	Group      Group
+	Permission Permission
+	Keys       []string
+}
+
+// Policy decides whether identity is allowed to perform request according to its roles,
+// admin role is built-in and allows everything except cluster-internal RPCs which are allowed only to nodes
+type Policy struct {
+	logger *slog.Logger
+	roles  map[string]Role
+}
+
+func NewPolicy(logger *slog.Logger, conf PolicyConfig) (*Policy, error) {
+	if logger == nil {
+		return nil, errors.New("logger required")
+	}
+
+	logger = logger.With(sl.Component("auth.Policy"))
+
+	roles := make(map[string]Role, len(conf.Roles))
+	for _, role := range conf.Roles {
+		if role.Name == "" {
+			return nil, errors.New("role name required")
+		}
+		if role.Name == RoleAdmin || role.Name == RoleNode {
+			return nil, fmt.Errorf("role %s is built-in and cannot be redefined", role.Name)
+		}
+		if _, ok := roles[role.Name]; ok {
+			return nil, fmt.Errorf("role %s is defined twice", role.Name)
+		}
+
+		for _, rule := range role.Rules {
+			if err := rule.validate(); err != nil {
+				return nil, fmt.Errorf("invalid rule of role %s: %w", role.Name, err)
+			}
+		}
+
+		roles[role.Name] = role
+	}
+
+	logger.Debug("created successfully", sl.Conf(conf))
+
+	return &Policy{
+		logger: logger,
+		roles:  roles,
+	}, nil
+}
+
+// Authorize returns ErrPermissionDenied if identity is not allowed to perform request,
+// every key of request must be both in token scope and granted by some role
+func (p *Policy) Authorize(id Identity, req Request) error {
+	for _, key := range req.Keys {
+		if !id.InScope(key) {
+			return fmt.Errorf("%w: %s is out of token scope", ErrPermissionDenied, key)
+		}
+	}
+
+	if req.Group == GroupInternal {
+		if !id.HasRole(RoleNode) {
+			return fmt.Errorf("%w: only cluster nodes are allowed", ErrPermissionDenied)
+		}
+		return nil
+	}
+
+	if id.HasRole(RoleAdmin) {
+		return nil
+	}
+
+	if req.Group != GroupData || len(req.Keys) == 0 {
+		if !p.granted(id, req.Group, req.Permission, "") {
+			return fmt.Errorf("%w: %s permission on %s required", ErrPermissionDenied, req.Permission, req.Group)
+		}
+		return nil
+	}
+
+	for _, key := range req.Keys {
+		if !p.granted(id, req.Group, req.Permission, key) {
+			return fmt.Errorf("%w: %s permission on %s required", ErrPermissionDenied, req.Permission, key)
+		}
+	}
+
+	return nil
+}
+
+// Defined reports whether role can be granted to user, node role is reserved for cluster nodes
+func (p *Policy) Defined(role string) bool {
+	if role == RoleAdmin {
+		return true
+	}
+
+	_, ok := p.roles[role]
+	return ok
+}
+
+func (p *Policy) granted(id Identity, group Group, permission Permission, key string) bool {
+	for _, name := range id.Roles {
+		role, ok := p.roles[name]
+		if !ok {
+			continue
+		}
+
+		for _, rule := range role.Rules {
+			if rule.Group == group && strings.HasPrefix(key, rule.Prefix) && rule.allows(permission) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func (r Rule) allows(permission Permission) bool {
+	return slices.Contains(r.Permissions, permission) || slices.Contains(r.Permissions, PermissionAdmin)
+}
+
+func (r Rule) validate() error {
+	switch r.Group {
+	case GroupData:
+	case GroupCluster:
+		if r.Prefix != "" {
+			return errors.New("prefix can be used only with data group")
+		}
+	default:
+		return fmt.Errorf("unknown group %q", r.Group)
+	}
+
+	for _, permission := range r.Permissions {
+		switch permission {
+		case PermissionRead, PermissionWrite, PermissionAdmin:
+		default:
+			return fmt.Errorf("unknown permission %q", permission)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,210 @@
+package auth
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"kvstore/internal/sl"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+const (
+	defaultTokenTTL = 15 * time.Minute
+	// clockSkew is tolerated difference between clocks of the node which issued token and the one which verifies it
+	clockSkew       = 30 * time.Second
+	minKeyLength    = 32
+	signatureMethod = "HS256"
+)
+
+var (
+	ErrTokensDisabled = errors.New("tokens are disabled, no signing keys configured")
+	ErrInvalidToken   = errors.New("invalid token")
+	ErrTokenExpired   = errors.New("token expired")
+)
+
+type TokensConfig struct {
+	Issuer string
+	TTL    time.Duration
+	// SigningKey is id of the key new tokens are signed with, other keys are used only to verify tokens,
+	// so keys are rotated by adding a new key, switching signing key to it and removing the old one after TTL
+	SigningKey string
+	// Keys are HMAC secrets by their ids, they must be the same on every node
+	Keys map[string]sl.Secret
+}
+
+type header struct {
+	Algorithm string `json:"alg"`
+	Type      string `json:"typ"`
+	KeyID     string `json:"kid"`
+}
+
+type claims struct {
+	Subject   string   `json:"sub"`
+	Roles     []string `json:"roles,omitempty"`
+	Scope     []string `json:"scope,omitempty"`
+	Issuer    string   `json:"iss,omitempty"`
+	IssuedAt  int64    `json:"iat"`
+	ExpiresAt int64    `json:"exp"`
+}
+
+// Tokens issues and verifies short-lived HMAC signed JWTs
+type Tokens struct {
+	logger     *slog.Logger
+	issuer     string
+	ttl        time.Duration
+	signingKey string
+	keys       map[string][]byte
+}
+
+func NewTokens(logger *slog.Logger, conf TokensConfig) (*Tokens, error) {
+	if logger == nil {
+		return nil, errors.New("logger required")
+	}
+
+	logger = logger.With(sl.Component("auth.Tokens"))
+
+	if conf.TTL <= 0 {
+		conf.TTL = defaultTokenTTL
+	}
+
+	keys := make(map[string][]byte, len(conf.Keys))
+	for id, secret := range conf.Keys {
+		if len(secret) < minKeyLength {
+			return nil, fmt.Errorf("key %q must be at least %d bytes long", id, minKeyLength)
+		}
+		keys[id] = []byte(secret)
+	}
+
+	if _, ok := keys[conf.SigningKey]; len(keys) > 0 && !ok {
+		return nil, fmt.Errorf("signing key %q is not among keys", conf.SigningKey)
+	}
+
+	logger.Debug("created successfully", sl.Conf(conf))
+
+	return &Tokens{
+		logger:     logger,
+		issuer:     conf.Issuer,
+		ttl:        conf.TTL,
+		signingKey: conf.SigningKey,
+		keys:       keys,
+	}, nil
+}
+
+// Issue signs token for identity, the token grants access only to keys with given prefixes if scope is not empty
+func (t *Tokens) Issue(id Identity, scope []string) (string, time.Time, error) {
+	if len(t.keys) == 0 {
+		return "", time.Time{}, ErrTokensDisabled
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(t.ttl)
+
+	headerPart, err := encodePart(header{
+		Algorithm: signatureMethod,
+		Type:      "JWT",
+		KeyID:     t.signingKey,
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	claimsPart, err := encodePart(claims{
+		Subject:   id.User,
+		Roles:     id.Roles,
+		Scope:     scope,
+		Issuer:    t.issuer,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+
+	signed := headerPart + "." + claimsPart
+	signature := sign(t.keys[t.signingKey], signed)
+
+	return signed + "." + signature, expiresAt, nil
+}
+
+// Verify checks signature, expiry and issuer of token and returns identity it was issued for
+func (t *Tokens) Verify(token string) (Identity, error) {
+	if len(t.keys) == 0 {
+		return Identity{}, ErrTokensDisabled
+	}
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return Identity{}, ErrInvalidToken
+	}
+
+	var h header
+	if err := decodePart(parts[0], &h); err != nil {
+		return Identity{}, ErrInvalidToken
+	}
+	if h.Algorithm != signatureMethod {
+		return Identity{}, fmt.Errorf("%w: unsupported algorithm %q", ErrInvalidToken, h.Algorithm)
+	}
+
+	key, ok := t.keys[h.KeyID]
+	if !ok {
+		return Identity{}, fmt.Errorf("%w: unknown key %q", ErrInvalidToken, h.KeyID)
+	}
+
+	expected := sign(key, parts[0]+"."+parts[1])
+	if !hmac.Equal([]byte(expected), []byte(parts[2])) {
+		return Identity{}, fmt.Errorf("%w: signature mismatch", ErrInvalidToken)
+	}
+
+	var c claims
+	if err := decodePart(parts[1], &c); err != nil {
+		return Identity{}, ErrInvalidToken
+	}
+
+	now := time.Now()
+	if now.After(time.Unix(c.ExpiresAt, 0).Add(clockSkew)) {
+		return Identity{}, ErrTokenExpired
+	}
+	if now.Add(clockSkew).Before(time.Unix(c.IssuedAt, 0)) {
+		return Identity{}, fmt.Errorf("%w: issued in the future", ErrInvalidToken)
+	}
+	if c.Issuer != t.issuer {
+		return Identity{}, fmt.Errorf("%w: unexpected issuer %q", ErrInvalidToken, c.Issuer)
+	}
+	if c.Subject == "" {
+		return Identity{}, fmt.Errorf("%w: subject missing", ErrInvalidToken)
+	}
+
+	return Identity{
+		User:  c.Subject,
+		Roles: c.Roles,
+		Scope: c.Scope,
+	}, nil
+}
+
+func sign(key []byte, signed string) string {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(signed))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func encodePart(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodePart(part string, v any) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(data, v)
+}
@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"context"
+	"errors"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrUserExists = errors.New("user already exists")
+	ErrNoUser     = errors.New("no such user")
+)
+
+const (
+	// RoleAdmin allows to manage users and cluster
+	RoleAdmin = "admin"
+	// RoleNode is given to other nodes of cluster and allows cluster-internal RPCs
+	RoleNode = "node"
+)
+
+type User struct {
+	Name         string   `json:"name"`
+	PasswordHash []byte   `json:"password_hash"`
+	Roles        []string `json:"roles"`
+}
+
+// Users is user database which is replicated via raft, so it is changed only by FSM
+type Users struct {
+	mu    *sync.RWMutex
+	users map[string]User
+}
+
+func NewUsers() *Users {
+	return &Users{
+		mu:    new(sync.RWMutex),
+		users: make(map[string]User),
+	}
+}
+
+func (u *Users) Get(_ context.Context, name string) (User, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	user, ok := u.users[name]
+	if !ok {
+		return User{}, ErrNoUser
+	}
+
+	return user, nil
+}
+
+func (u *Users) List(_ context.Context) ([]User, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	users := slices.Collect(maps.Values(u.users))
+	slices.SortFunc(users, func(a, b User) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	return users, nil
+}
+
+func (u *Users) Create(_ context.Context, user User) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if _, ok := u.users[user.Name]; ok {
+		return ErrUserExists
+	}
+
+	u.users[user.Name] = user
+
+	return nil
+}
+
+// SetPasswordHash changes only password of current record, so roles changed concurrently are kept
+func (u *Users) SetPasswordHash(_ context.Context, name string, hash []byte) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	user, ok := u.users[name]
+	if !ok {
+		return ErrNoUser
+	}
+
+	user.PasswordHash = hash
+	u.users[name] = user
+
+	return nil
+}
+
+func (u *Users) Delete(_ context.Context, name string) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if _, ok := u.users[name]; !ok {
+		return ErrNoUser
+	}
+
+	delete(u.users, name)
+
+	return nil
+}
+
+func (u *Users) Snapshot(_ context.Context) (map[string]User, error) {
+	u.mu.RLock()
+	defer u.mu.RUnlock()
+
+	return maps.Clone(u.users), nil
+}
+
+func (u *Users) Load(_ context.Context, users map[string]User) error {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	if users == nil {
+		users = make(map[string]User)
+	}
+	u.users = users
+
+	return nil
+}
@@ -0,0 +1,251 @@
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"kvstore/internal/sl"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+)
+
+const defaultReloadInterval = 10 * time.Second
+
+type ClientAuth string
+
+const (
+	ClientAuthNone             ClientAuth = "none"
+	ClientAuthRequest          ClientAuth = "request"
+	ClientAuthRequire          ClientAuth = "require"
+	ClientAuthVerifyIfGiven    ClientAuth = "verify_if_given"
+	ClientAuthRequireAndVerify ClientAuth = "require_and_verify"
+)
+
+var clientAuthTypes = map[ClientAuth]tls.ClientAuthType{
+	"":                         tls.NoClientCert,
+	ClientAuthNone:             tls.NoClientCert,
+	ClientAuthRequest:          tls.RequestClientCert,
+	ClientAuthRequire:          tls.RequireAnyClientCert,
+	ClientAuthVerifyIfGiven:    tls.VerifyClientCertIfGiven,
+	ClientAuthRequireAndVerify: tls.RequireAndVerifyClientCert,
+}
+
+type Config struct {
+	CertFile       string
+	KeyFile        string
+	CAFile         string
+	ClientAuth     ClientAuth
+	ReloadInterval time.Duration
+}
+
+// Reloader keeps certificates loaded from files and reloads them when files change,
+// tls configs made by it always use the latest loaded certificates
+type Reloader struct {
+	logger         *slog.Logger
+	certFile       string
+	keyFile        string
+	caFile         string
+	clientAuth     tls.ClientAuthType
+	reloadInterval time.Duration
+
+	mu       *sync.RWMutex
+	cert     *tls.Certificate
+	pool     *x509.CertPool
+	modTimes map[string]time.Time
+}
+
+func New(logger *slog.Logger, conf Config) (*Reloader, error) {
+	if logger == nil {
+		return nil, errors.New("logger required")
+	}
+
+	logger = logger.With(sl.Component("certs.Reloader"))
+
+	logger.Debug("creating certificates reloader", sl.Conf(conf))
+
+	if conf.CertFile == "" {
+		return nil, errors.New("cert file required")
+	}
+	if conf.KeyFile == "" {
+		return nil, errors.New("key file required")
+	}
+
+	clientAuth, ok := clientAuthTypes[conf.ClientAuth]
+	if !ok {
+		return nil, fmt.Errorf("unknown client auth %q", conf.ClientAuth)
+	}
+	if clientAuth >= tls.VerifyClientCertIfGiven && conf.CAFile == "" {
+		return nil, errors.New("ca file required to verify client certificates")
+	}
+	if conf.ReloadInterval <= 0 {
+		conf.ReloadInterval = defaultReloadInterval
+	}
+
+	r := &Reloader{
+		logger:         logger,
+		certFile:       conf.CertFile,
+		keyFile:        conf.KeyFile,
+		caFile:         conf.CAFile,
+		clientAuth:     clientAuth,
+		reloadInterval: conf.ReloadInterval,
+		mu:             new(sync.RWMutex),
+		modTimes:       make(map[string]time.Time, 3),
+	}
+
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+
+	logger.Debug("created successfully", sl.Conf(conf))
+
+	return r, nil
+}
+
+// ServerConfig is tls config for servers which verifies clients according to client auth mode
+func (r *Reloader) ServerConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				Certificates: []tls.Certificate{*r.cert},
+				ClientAuth:   r.clientAuth,
+				ClientCAs:    r.pool,
+			}, nil
+		},
+	}
+}
+
+// ClientConfig is tls config for connections to other nodes, it presents node certificate
+// and verifies server against CA if it is provided or against system roots otherwise
+func (r *Reloader) ClientConfig() *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		// verification is done in VerifyConnection to use reloaded CA
+		InsecureSkipVerify: true,
+		GetClientCertificate: func(*tls.CertificateRequestInfo) (*tls.Certificate, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+
+			return r.cert, nil
+		},
+		VerifyConnection: func(cs tls.ConnectionState) error {
+			r.mu.RLock()
+			pool := r.pool
+			r.mu.RUnlock()
+
+			if len(cs.PeerCertificates) == 0 {
+				return errors.New("server did not provide certificate")
+			}
+
+			intermediates := x509.NewCertPool()
+			for _, cert := range cs.PeerCertificates[1:] {
+				intermediates.AddCert(cert)
+			}
+
+			_, err := cs.PeerCertificates[0].Verify(x509.VerifyOptions{
+				DNSName:       cs.ServerName,
+				Roots:         pool,
+				Intermediates: intermediates,
+			})
+
+			return err
+		},
+	}
+}
+
+// Run checks files periodically and reloads certificates if some of them changed
+func (r *Reloader) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.reloadInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		if !r.changed() {
+			continue
+		}
+
+		if err := r.load(); err != nil {
+			r.logger.Error("cannot reload certificates, keep using previous ones", sl.Error(err))
+			continue
+		}
+
+		r.logger.Info("reloaded certificates")
+	}
+}
+
+func (r *Reloader) load() error {
+	modTimes := make(map[string]time.Time, 3)
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			return fmt.Errorf("cannot stat %s: %w", file, err)
+		}
+		modTimes[file] = info.ModTime()
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("cannot load key pair: %w", err)
+	}
+
+	var pool *x509.CertPool
+	if r.caFile != "" {
+		pem, err := os.ReadFile(r.caFile)
+		if err != nil {
+			return fmt.Errorf("cannot read ca file: %w", err)
+		}
+
+		pool = x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificates found in ca file %s", r.caFile)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cert = &cert
+	r.pool = pool
+	r.modTimes = modTimes
+
+	return nil
+}
+
+func (r *Reloader) changed() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, file := range r.files() {
+		info, err := os.Stat(file)
+		if err != nil {
+			r.logger.Warn("cannot stat certificate file", slog.String("file", file), sl.Error(err))
+			continue
+		}
+		if !info.ModTime().Equal(r.modTimes[file]) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *Reloader) files() []string {
+	files := []string{r.certFile, r.keyFile}
+	if r.caFile != "" {
+		files = append(files, r.caFile)
+	}
+	return files
+}
@@ -3,14 +3,23 @@ package config
 import (
 	"encoding/json"
 	"fmt"
-	"github.com/hashicorp/go-hclog"
 	"gopkg.in/yaml.v3"
+	"kvstore/internal/admin"
+	"kvstore/internal/audit"
+	"kvstore/internal/auth"
+	"kvstore/internal/certs"
 	"kvstore/internal/core"
+	"kvstore/internal/discovery"
 	"kvstore/internal/grpc/clients"
 	"kvstore/internal/grpc/servers"
+	"kvstore/internal/grpc/transport"
 	"kvstore/internal/raft"
+	"kvstore/internal/ratelimit"
 	"kvstore/internal/sl"
+	"kvstore/internal/slowlog"
+	"kvstore/internal/tracing"
 	"log/slog"
+	"net"
 	"os"
 	"strings"
 	"time"
@@ -28,27 +37,202 @@ type Config struct {
 	StoreConfig      Store      `yaml:"storage"`
 	GRPCServerConfig GRPCServer `yaml:"grpc_server"`
 	RaftConfig       Raft       `yaml:"raft"`
+	DiscoveryConfig  Discovery  `yaml:"discovery"`
+	TLSConfig        TLS        `yaml:"tls"`
+	AuthConfig       Auth       `yaml:"auth"`
+	ClusterConfig    Cluster    `yaml:"cluster"`
+	RateLimitConfig  RateLimit  `yaml:"rate_limit"`
+	AdminHTTPConfig  AdminHTTP  `yaml:"admin_http"`
+	TracingConfig    Tracing    `yaml:"tracing"`
+	AuditConfig      Audit      `yaml:"audit"`
+	SlowLogConfig    SlowLog    `yaml:"slow_log"`
+}
+
+type SlowLog struct {
+	Threshold  time.Duration `yaml:"threshold"`
+	SampleRate float64       `yaml:"sample_rate"`
+}
+
+type Audit struct {
+	File       string `yaml:"file"`
+	MaxSize    int64  `yaml:"max_size"`
+	MaxBackups int    `yaml:"max_backups"`
+	Values     string `yaml:"values"`
+}
+
+type Tracing struct {
+	Exporter    string  `yaml:"exporter"`
+	Endpoint    string  `yaml:"endpoint"`
+	Insecure    bool    `yaml:"insecure"`
+	File        string  `yaml:"file"`
+	SampleRatio float64 `yaml:"sample_ratio"`
+	ServiceName string  `yaml:"service_name"`
+}
+
+type AdminHTTP struct {
+	Address     string `yaml:"address"`
+	RequireAuth bool   `yaml:"require_auth"`
+	TLS         bool   `yaml:"tls"`
+	PProf       bool   `yaml:"pprof"`
+}
+
+type RateLimit struct {
+	Default       Limit         `yaml:"default"`
+	Users         []UserLimit   `yaml:"users"`
+	Methods       []MethodLimit `yaml:"methods"`
+	Address       Limit         `yaml:"address"`
+	CleanInterval time.Duration `yaml:"clean_interval"`
+}
+
+type Limit struct {
+	Rate  float64 `yaml:"rate"`
+	Burst int     `yaml:"burst"`
+}
+
+type UserLimit struct {
+	User  string  `yaml:"user"`
+	Rate  float64 `yaml:"rate"`
+	Burst int     `yaml:"burst"`
+}
+
+type MethodLimit struct {
+	Method string  `yaml:"method"`
+	Rate   float64 `yaml:"rate"`
+	Burst  int     `yaml:"burst"`
+}
+
+type Cluster struct {
+	Secret           string   `yaml:"secret"`
+	NodeNames        []string `yaml:"node_names"`
+	AllowedNodes     []string `yaml:"allowed_nodes"`
+	RequireJoinToken bool     `yaml:"require_join_token"`
+	JoinToken        string   `yaml:"join_token"`
+}
+
+type Auth struct {
+	Tokens Tokens `yaml:"tokens"`
+	Roles  []Role `yaml:"roles"`
+}
+
+type Role struct {
+	Name  string     `yaml:"name"`
+	Rules []RoleRule `yaml:"rules"`
+}
+
+type RoleRule struct {
+	Group       string   `yaml:"group"`
+	Prefix      string   `yaml:"prefix"`
+	Permissions []string `yaml:"permissions"`
+}
+
+type Tokens struct {
+	Issuer     string        `yaml:"issuer"`
+	TTL        time.Duration `yaml:"ttl"`
+	SigningKey string        `yaml:"signing_key"`
+	Keys       []TokenKey    `yaml:"keys"`
+}
+
+type TokenKey struct {
+	ID     string `yaml:"id"`
+	Secret string `yaml:"secret"`
+}
+
+type TLS struct {
+	CertFile       string        `yaml:"cert_file"`
+	KeyFile        string        `yaml:"key_file"`
+	CAFile         string        `yaml:"ca_file"`
+	ClientAuth     string        `yaml:"client_auth"`
+	ReloadInterval time.Duration `yaml:"reload_interval"`
 }
 
 type Logger struct {
 	Level int `yaml:"level"`
+	// Components are levels of components overriding global one, e.g. raft.FSM: debug
+	Components map[string]string `yaml:"components"`
+	Redact     Redact            `yaml:"redact"`
+}
+
+type Redact struct {
+	Keys      string `yaml:"keys"`
+	Values    string `yaml:"values"`
+	MaxLength int    `yaml:"max_length"`
 }
 
 type Store struct {
 	CleanInterval    time.Duration `yaml:"clean_interval"`
 	MaxCleanDuration time.Duration `yaml:"clean_duration"`
 	InitialCapacity  int64         `yaml:"initial_capacity"`
+	Quotas           Quotas        `yaml:"quotas"`
+	Limits           SizeLimits    `yaml:"limits"`
+}
+
+type SizeLimits struct {
+	MaxKeyLength   int `yaml:"max_key_length"`
+	MaxValueSize   int `yaml:"max_value_size"`
+	MaxRequestSize int `yaml:"max_request_size"`
+}
+
+type Quotas struct {
+	Prefixes    []PrefixQuota `yaml:"prefixes"`
+	Users       []UserQuota   `yaml:"users"`
+	DefaultUser Quota         `yaml:"default_user"`
+}
+
+type Quota struct {
+	MaxKeys  int64 `yaml:"max_keys"`
+	MaxBytes int64 `yaml:"max_bytes"`
+}
+
+type PrefixQuota struct {
+	Prefix   string `yaml:"prefix"`
+	MaxKeys  int64  `yaml:"max_keys"`
+	MaxBytes int64  `yaml:"max_bytes"`
+}
+
+type UserQuota struct {
+	User     string `yaml:"user"`
+	MaxKeys  int64  `yaml:"max_keys"`
+	MaxBytes int64  `yaml:"max_bytes"`
 }
 
 type GRPCServer struct {
 	ConnectionTimeout time.Duration `yaml:"connection_timeout"`
 }
 
+type Discovery struct {
+	DNS DNSDiscovery `yaml:"dns"`
+}
+
+type DNSDiscovery struct {
+	Name     string        `yaml:"name"`
+	Port     string        `yaml:"port"`
+	SRV      bool          `yaml:"srv"`
+	Interval time.Duration `yaml:"interval"`
+}
+
+const (
+	RaftTransportTCP  = "tcp"
+	RaftTransportGRPC = "grpc"
+)
+
 type Raft struct {
-	NodeID          string        `yaml:"-"`
+	NodeID          string        `yaml:"node_id"`
+	Transport       string        `yaml:"transport"`
 	TCPTimeout      time.Duration `yaml:"tcp_timeout"`
 	MaxPool         int           `yaml:"max_pool"`
 	SnapshotsRetain int           `yaml:"snapshots_retain"`
+	JoinTimeout     time.Duration `yaml:"join_timeout"`
+
+	Preset             string        `yaml:"preset"`
+	HeartbeatTimeout   time.Duration `yaml:"heartbeat_timeout"`
+	ElectionTimeout    time.Duration `yaml:"election_timeout"`
+	LeaderLeaseTimeout time.Duration `yaml:"leader_lease_timeout"`
+	CommitTimeout      time.Duration `yaml:"commit_timeout"`
+	SnapshotInterval   time.Duration `yaml:"snapshot_interval"`
+	SnapshotThreshold  uint64        `yaml:"snapshot_threshold"`
+	TrailingLogs       uint64        `yaml:"trailing_logs"`
+	MaxAppendEntries   int           `yaml:"max_append_entries"`
+	BatchApply         bool          `yaml:"batch_apply"`
 }
 
 func Read() (*Config, error) {
@@ -73,32 +257,87 @@ func Read() (*Config, error) {
 	c.choose(&c.Username, username)
 	c.choose(&c.Password, password)
 	c.choose(&c.Advertise, advertise)
+	c.choose(&c.RaftConfig.NodeID, nodeID)
+	c.choose(&c.DiscoveryConfig.DNS.Name, discoveryDNS)
+	c.choose(&c.ClusterConfig.JoinToken, joinToken)
+
+	if c.DiscoveryConfig.DNS.Port == "" {
+		c.DiscoveryConfig.DNS.Port = c.PublicPort
+	}
 
 	if c.Advertise == "" {
 		c.Advertise = c.address("localhost", c.InternalPort)
 	}
 
-	c.RaftConfig.NodeID = c.Advertise //todo it is bad
+	if *bootstrapExpect > 0 && !c.hasPeers() {
+		return nil, fmt.Errorf("peers to bootstrap cluster with must be provided via join-to or discovery")
+	}
+	// nodes found by discovery never bootstrap alone, so without expected number of them nobody bootstraps
+	if *bootstrapExpect == 0 && c.DiscoveryConfig.DNS.Name != "" {
+		return nil, fmt.Errorf("bootstrap-expect must be provided if peers are discovered via dns")
+	}
+
+	switch c.RaftConfig.Transport {
+	case "":
+		c.RaftConfig.Transport = RaftTransportTCP
+	case RaftTransportTCP, RaftTransportGRPC:
+	default:
+		return nil, fmt.Errorf("unknown raft transport %q", c.RaftConfig.Transport)
+	}
+
+	// older versions used advertised raft address as node id
+	id, err := raft.NodeID(c.DataPath, raft.ServerID(c.RaftConfig.NodeID), raft.ServerID(c.Advertise))
+	if err != nil {
+		return nil, fmt.Errorf("cannot get node id: %w", err)
+	}
+
+	c.RaftConfig.NodeID = string(id)
 
 	return &c, nil
 }
 
-func (c *Config) Logger() *slog.HandlerOptions {
-	level := slog.Level(c.LoggerConfig.Level)
+// LogLevels are levels which are shared by every logger, so they can be changed at runtime
+func (c *Config) LogLevels() (*sl.Levels, error) {
+	global := slog.Level(c.LoggerConfig.Level)
 	if *verbose {
-		level = slog.LevelDebug
+		global = slog.LevelDebug
+	}
+
+	components := make(map[string]slog.Level, len(c.LoggerConfig.Components))
+	for component, name := range c.LoggerConfig.Components {
+		level, err := sl.ParseLevel(name)
+		if err != nil {
+			return nil, fmt.Errorf("logger.components.%s: %w", component, err)
+		}
+		components[component] = level
+	}
+
+	return sl.NewLevels(global, components), nil
+}
+
+func (c *Config) Redaction() sl.RedactConfig {
+	return sl.RedactConfig{
+		Keys:      c.LoggerConfig.Redact.Keys,
+		Values:    c.LoggerConfig.Redact.Values,
+		MaxLength: c.LoggerConfig.Redact.MaxLength,
 	}
+}
 
+// Logger is options of handler which is wrapped by sl.LevelHandler, so records are filtered by it
+func (c *Config) Logger() *slog.HandlerOptions {
 	return &slog.HandlerOptions{
 		AddSource: false,
-		Level:     level,
-		//todo костыль чтобы slog был как hclog в идеалье реализовать hlog.Logger через slog
+		Level:     sl.LevelTrace,
+		// keys are named as hclog ones, so logs keep the format they had when hashicorp libraries used hclog
 		ReplaceAttr: func(groups []string, a slog.Attr) slog.Attr {
 			switch a.Key {
 			case slog.TimeKey:
 				return slog.Attr{Key: "@timestamp", Value: a.Value}
 			case slog.LevelKey:
-				return slog.Attr{Key: "@level", Value: slog.StringValue(strings.ToLower(a.Value.String()))}
+				if level, ok := a.Value.Any().(slog.Level); ok {
+					return slog.Attr{Key: "@level", Value: slog.StringValue(sl.LevelName(level))}
+				}
+				return slog.Attr{Key: "@level", Value: a.Value}
 			case slog.MessageKey:
 				return slog.Attr{Key: "@message", Value: a.Value}
 			case sl.MessageComponent:
@@ -110,75 +349,312 @@ func (c *Config) Logger() *slog.HandlerOptions {
 	}
 }
 
-func (c *Config) HashicorpLogger() *hclog.LoggerOptions {
-	return &hclog.LoggerOptions{
-		Name:                     "hashicorp.Raft.(raft.internal)",
-		Level:                    hclog.Level(c.LoggerConfig.Level),
-		Output:                   os.Stderr,
-		Mutex:                    nil,
-		JSONFormat:               true,
-		JSONEscapeDisabled:       true,
-		IncludeLocation:          false,
-		AdditionalLocationOffset: 0,
-		TimeFormat:               "",
-		TimeFn:                   time.Now,
-		DisableTime:              false,
-		Color:                    0,
-		ColorHeaderOnly:          false,
-		ColorHeaderAndFields:     false,
-		Exclude:                  nil,
-		IndependentLevels:        false,
-		SyncParentLevel:          false,
-		SubloggerHook:            nil,
+// TLSEnabled reports whether client, join and raft traffic must be encrypted
+func (c *Config) TLSEnabled() bool {
+	return c.TLSConfig.CertFile != "" || c.TLSConfig.KeyFile != ""
+}
+
+func (c *Config) TLS() certs.Config {
+	return certs.Config{
+		CertFile:       c.TLSConfig.CertFile,
+		KeyFile:        c.TLSConfig.KeyFile,
+		CAFile:         c.TLSConfig.CAFile,
+		ClientAuth:     certs.ClientAuth(c.TLSConfig.ClientAuth),
+		ReloadInterval: c.TLSConfig.ReloadInterval,
+	}
+}
+
+func (c *Config) Authenticator() auth.AuthenticatorConfig {
+	return auth.AuthenticatorConfig{
+		Username:      c.Username,
+		Password:      sl.Secret(c.Password),
+		ClusterSecret: sl.Secret(c.ClusterConfig.Secret),
+		NodeNames:     nonEmpty(c.ClusterConfig.NodeNames),
+	}
+}
+
+// Tokens skips keys with empty secrets, so keys can be provided through optional env variables
+func (c *Config) Tokens() auth.TokensConfig {
+	keys := make(map[string]sl.Secret, len(c.AuthConfig.Tokens.Keys))
+	for _, key := range c.AuthConfig.Tokens.Keys {
+		if key.Secret != "" {
+			keys[key.ID] = sl.Secret(key.Secret)
+		}
+	}
+
+	return auth.TokensConfig{
+		Issuer:     c.AuthConfig.Tokens.Issuer,
+		TTL:        c.AuthConfig.Tokens.TTL,
+		SigningKey: c.AuthConfig.Tokens.SigningKey,
+		Keys:       keys,
+	}
+}
+
+func (c *Config) Policy() auth.PolicyConfig {
+	roles := make([]auth.Role, 0, len(c.AuthConfig.Roles))
+	for _, role := range c.AuthConfig.Roles {
+		rules := make([]auth.Rule, 0, len(role.Rules))
+		for _, rule := range role.Rules {
+			permissions := make([]auth.Permission, 0, len(rule.Permissions))
+			for _, permission := range rule.Permissions {
+				permissions = append(permissions, auth.Permission(permission))
+			}
+
+			rules = append(rules, auth.Rule{
+				Group:       auth.Group(rule.Group),
+				Prefix:      rule.Prefix,
+				Permissions: permissions,
+			})
+		}
+
+		roles = append(roles, auth.Role{
+			Name:  role.Name,
+			Rules: rules,
+		})
+	}
+
+	return auth.PolicyConfig{
+		Roles: roles,
+	}
+}
+
+func (c *Config) RateLimit() ratelimit.Config {
+	users := make(map[string]ratelimit.Limit, len(c.RateLimitConfig.Users))
+	for _, limit := range c.RateLimitConfig.Users {
+		users[limit.User] = ratelimit.Limit{Rate: limit.Rate, Burst: limit.Burst}
+	}
+
+	methods := make(map[string]ratelimit.Limit, len(c.RateLimitConfig.Methods))
+	for _, limit := range c.RateLimitConfig.Methods {
+		methods[limit.Method] = ratelimit.Limit{Rate: limit.Rate, Burst: limit.Burst}
+	}
+
+	return ratelimit.Config{
+		Default: ratelimit.Limit{
+			Rate:  c.RateLimitConfig.Default.Rate,
+			Burst: c.RateLimitConfig.Default.Burst,
+		},
+		Users:   users,
+		Methods: methods,
+		Address: ratelimit.Limit{
+			Rate:  c.RateLimitConfig.Address.Rate,
+			Burst: c.RateLimitConfig.Address.Burst,
+		},
+		CleanInterval: c.RateLimitConfig.CleanInterval,
+	}
+}
+
+func (c *Config) SlowLog() slowlog.Config {
+	return slowlog.Config{
+		Threshold:  c.SlowLogConfig.Threshold,
+		SampleRate: c.SlowLogConfig.SampleRate,
+	}
+}
+
+func (c *Config) Audit() audit.Config {
+	return audit.Config{
+		File:       c.AuditConfig.File,
+		MaxSize:    c.AuditConfig.MaxSize,
+		MaxBackups: c.AuditConfig.MaxBackups,
+		Values:     c.AuditConfig.Values,
+	}
+}
+
+func (c *Config) Tracing() tracing.Config {
+	return tracing.Config{
+		Exporter:    c.TracingConfig.Exporter,
+		Endpoint:    c.TracingConfig.Endpoint,
+		Insecure:    c.TracingConfig.Insecure,
+		File:        c.TracingConfig.File,
+		SampleRatio: c.TracingConfig.SampleRatio,
+		ServiceName: c.TracingConfig.ServiceName,
+		NodeID:      c.RaftConfig.NodeID,
+	}
+}
+
+func (c *Config) AdminHTTP() admin.Config {
+	return admin.Config{
+		Address:     c.AdminHTTPConfig.Address,
+		RequireAuth: c.AdminHTTPConfig.RequireAuth,
+		TLS:         c.AdminHTTPConfig.TLS,
+		PProf:       c.AdminHTTPConfig.PProf,
+	}
+}
+
+func (c *Config) SizeLimits() core.SizeLimits {
+	return core.SizeLimits{
+		MaxKeyLength:   c.StoreConfig.Limits.MaxKeyLength,
+		MaxValueSize:   c.StoreConfig.Limits.MaxValueSize,
+		MaxRequestSize: c.StoreConfig.Limits.MaxRequestSize,
 	}
 }
 
 func (c *Config) Store() core.Config {
+	quotas := c.StoreConfig.Quotas
+
+	prefixes := make([]core.PrefixQuota, 0, len(quotas.Prefixes))
+	for _, quota := range quotas.Prefixes {
+		prefixes = append(prefixes, core.PrefixQuota{
+			Prefix: quota.Prefix,
+			Quota:  core.Quota{MaxKeys: quota.MaxKeys, MaxBytes: quota.MaxBytes},
+		})
+	}
+
+	users := make(map[string]core.Quota, len(quotas.Users))
+	for _, quota := range quotas.Users {
+		users[quota.User] = core.Quota{MaxKeys: quota.MaxKeys, MaxBytes: quota.MaxBytes}
+	}
+
 	return core.Config{
 		CleanInterval:   c.StoreConfig.CleanInterval,
 		CleanDuration:   c.StoreConfig.MaxCleanDuration,
 		InitialCapacity: c.StoreConfig.InitialCapacity,
+		Quotas: core.QuotasConfig{
+			Prefixes: prefixes,
+			Users:    users,
+			DefaultUser: core.Quota{
+				MaxKeys:  quotas.DefaultUser.MaxKeys,
+				MaxBytes: quotas.DefaultUser.MaxBytes,
+			},
+		},
 	}
 }
 
 func (c *Config) ExistingRaftClient() clients.RaftClientConfig {
 	return clients.RaftClientConfig{
-		Address:  *joinTo,
-		Username: c.Username,
-		Password: c.Password,
+		Username:      c.Username,
+		Password:      sl.Secret(c.Password),
+		ClusterSecret: sl.Secret(c.ClusterConfig.Secret),
+	}
+}
+
+func (c *Config) Discovery() discovery.Config {
+	return discovery.Config{
+		Seeds:    c.seeds(),
+		DNSName:  c.DiscoveryConfig.DNS.Name,
+		DNSPort:  c.DiscoveryConfig.DNS.Port,
+		SRV:      c.DiscoveryConfig.DNS.SRV,
+		Interval: c.DiscoveryConfig.DNS.Interval,
+	}
+}
+
+// RaftOverGRPC reports whether raft RPCs are sent through public grpc server instead of internal port
+func (c *Config) RaftOverGRPC() bool {
+	return c.RaftConfig.Transport == RaftTransportGRPC
+}
+
+func (c *Config) RaftTransport() transport.Config {
+	return transport.Config{
+		Address:       c.raftAdvertise(),
+		Timeout:       c.RaftConfig.TCPTimeout,
+		Username:      c.Username,
+		Password:      sl.Secret(c.Password),
+		ClusterSecret: sl.Secret(c.ClusterConfig.Secret),
 	}
 }
 
 func (c *Config) Raft() raft.Config {
 	return raft.Config{
-		RealAddress:       c.address(c.Host, c.InternalPort),
-		AdvertisedAddress: c.Advertise,
+		RealAddress:       c.raftAddress(),
+		AdvertisedAddress: c.raftAdvertise(),
 		NodeID:            c.RaftConfig.NodeID,
 		DataLocation:      c.DataPath,
 		SnapshotsRetain:   c.RaftConfig.SnapshotsRetain,
 		MaxPool:           c.RaftConfig.MaxPool,
 		TCPTimeout:        c.RaftConfig.TCPTimeout,
+		Recover:           *recoverCluster,
+		Tuning: raft.Tuning{
+			Preset:             raft.Preset(c.RaftConfig.Preset),
+			HeartbeatTimeout:   c.RaftConfig.HeartbeatTimeout,
+			ElectionTimeout:    c.RaftConfig.ElectionTimeout,
+			LeaderLeaseTimeout: c.RaftConfig.LeaderLeaseTimeout,
+			CommitTimeout:      c.RaftConfig.CommitTimeout,
+			SnapshotInterval:   c.RaftConfig.SnapshotInterval,
+			SnapshotThreshold:  c.RaftConfig.SnapshotThreshold,
+			TrailingLogs:       c.RaftConfig.TrailingLogs,
+			MaxAppendEntries:   c.RaftConfig.MaxAppendEntries,
+			BatchApply:         c.RaftConfig.BatchApply,
+		},
 	}
 }
 
 func (c *Config) ClusterNode() raft.ClusterNodeConfig {
 	return raft.ClusterNodeConfig{
 		ID:               raft.ServerID(c.RaftConfig.NodeID),
-		RealAddress:      raft.ServerAddress(c.address(c.Host, c.InternalPort)),
-		Advertise:        raft.ServerAddress(c.Advertise),
-		BootstrapCluster: *joinTo == "",
+		RealAddress:      raft.ServerAddress(c.raftAddress()),
+		Advertise:        raft.ServerAddress(c.raftAdvertise()),
+		ClientAddress:    c.clientAdvertise(),
+		BootstrapCluster: !c.hasPeers() && *bootstrapExpect == 0,
+		BootstrapExpect:  *bootstrapExpect,
+		JoinTimeout:      c.RaftConfig.JoinTimeout,
+		JoinToken:        sl.Secret(c.ClusterConfig.JoinToken),
+		AllowedNodes:     c.allowedNodes(),
+		RequireJoinToken: c.ClusterConfig.RequireJoinToken,
 	}
 }
 
 func (c *Config) GRPCServer() servers.Config {
+	// raft over grpc batches several entries of max_request_size into one message
+	maxRecvMsgSize := c.StoreConfig.Limits.MaxRequestSize
+	if c.RaftOverGRPC() {
+		maxRecvMsgSize = 0
+	}
+
 	return servers.Config{
 		Address:           c.address(c.Host, c.PublicPort),
-		Username:          c.Username,
-		Password:          c.Password,
 		ConnectionTimeout: c.GRPCServerConfig.ConnectionTimeout,
+		MaxRecvMsgSize:    maxRecvMsgSize,
 	}
 }
 
+func (c *Config) raftAddress() string {
+	if c.RaftOverGRPC() {
+		return c.address(c.Host, c.PublicPort)
+	}
+	return c.address(c.Host, c.InternalPort)
+}
+
+// raftAdvertise is advertised address with public port if raft goes through grpc server
+func (c *Config) raftAdvertise() string {
+	if !c.RaftOverGRPC() {
+		return c.Advertise
+	}
+
+	return c.clientAdvertise()
+}
+
+// clientAdvertise is advertised host with public port, clients are pointed to it when this node is the leader
+func (c *Config) clientAdvertise() string {
+	host, _, err := net.SplitHostPort(c.Advertise)
+	if err != nil {
+		host = c.Advertise
+	}
+
+	return c.address(host, c.PublicPort)
+}
+
+func (c *Config) hasPeers() bool {
+	return len(c.seeds()) > 0 || c.DiscoveryConfig.DNS.Name != ""
+}
+
+func (c *Config) seeds() []string {
+	var seeds []string
+	for _, seed := range strings.Split(*joinTo, ",") {
+		if seed = strings.TrimSpace(seed); seed != "" {
+			seeds = append(seeds, seed)
+		}
+	}
+	return seeds
+}
+
+func (c *Config) allowedNodes() []raft.ServerID {
+	var nodes []raft.ServerID
+	for _, node := range nonEmpty(c.ClusterConfig.AllowedNodes) {
+		nodes = append(nodes, raft.ServerID(node))
+	}
+	return nodes
+}
+
 func (c *Config) choose(target *string, flag *string) {
 	if target == nil || flag == nil {
 		return
@@ -207,3 +683,14 @@ func (c *Config) expandEnv() error {
 func (c *Config) address(host, port string) string {
 	return fmt.Sprintf("%s:%s", host, port)
 }
+
+// nonEmpty drops empty values which come from unset env variables
+func nonEmpty(values []string) []string {
+	var result []string
+	for _, value := range values {
+		if value = strings.TrimSpace(value); value != "" {
+			result = append(result, value)
+		}
+	}
+	return result
+}
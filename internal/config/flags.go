@@ -10,15 +10,32 @@ var (
 			"(You must not provide it if you run it with no custom DNS like docker DNS. "+
 			"And it must be either localhost or domain name)",
 	)
-	verbose    = flag.Bool("verbose", false, "Verbose output")
-	configPath = flag.String("config", "", "Path to configuration file")
-	dataPath   = flag.String("data", "", "Path to directory with kvstore data")
-	username   = flag.String("username", "", "Username to use for authentication")
-	password   = flag.String("password", "", "Password to use for authentication")
-	host       = flag.String("host", "0.0.0.0", "Host to use for authentication")
-	pPort      = flag.String("public-port", "8090", "Port to use for authentication")
-	iPort      = flag.String("internal-port", "3000", "Port to use for authentication")
-	joinTo     = flag.String("join-to", "", "Address of the leader or some node of cluster which is running, provide it to join to this cluster")
+	nodeID = flag.String("node-id", "",
+		"Stable identifier of this node in cluster, it is generated and stored in data directory on first start if not provided",
+	)
+	verbose      = flag.Bool("verbose", false, "Verbose output")
+	configPath   = flag.String("config", "", "Path to configuration file")
+	dataPath     = flag.String("data", "", "Path to directory with kvstore data")
+	username     = flag.String("username", "", "Username to use for authentication")
+	password     = flag.String("password", "", "Password to use for authentication")
+	host         = flag.String("host", "0.0.0.0", "Host to use for authentication")
+	pPort        = flag.String("public-port", "8090", "Port to use for authentication")
+	iPort        = flag.String("internal-port", "3000", "Port to use for authentication")
+	discoveryDNS = flag.String("discovery-dns", "",
+		"DNS name which is resolved periodically to addresses of cluster nodes to join to (e.g. headless service of StatefulSet)",
+	)
+	bootstrapExpect = flag.Int("bootstrap-expect", 0,
+		"Number of nodes to bootstrap cluster with, nodes find each other through -join-to list "+
+			"which must contain all of them, the cluster is bootstrapped once this number of nodes is known",
+	)
+	joinToken = flag.String("join-token", "",
+		"One-time token issued by administrator which is required to join to cluster if the cluster requires join tokens",
+	)
+	recoverCluster = flag.Bool("recover", false,
+		"Overwrite stored cluster configuration with this node only, use it to restore cluster which lost quorum "+
+			"and restart node without it after recovery",
+	)
+	joinTo = flag.String("join-to", "", "Comma separated addresses of the leader or some nodes of cluster which is running, provide it to join to this cluster")
 )
 
 func init() {
@@ -0,0 +1,33 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+)
+
+var ErrTooLarge = errors.New("too large")
+
+// SizeLimits caps size of entries, zero value of a field means no limit,
+// limits are checked when commands are applied too, so they must be the same on every node
+type SizeLimits struct {
+	MaxKeyLength   int
+	MaxValueSize   int
+	MaxRequestSize int
+}
+
+func (l SizeLimits) CheckEntry(key Key, value Value) error {
+	if l.MaxKeyLength > 0 && len(key) > l.MaxKeyLength {
+		return fmt.Errorf("key is %w: %d bytes exceeds max_key_length %d", ErrTooLarge, len(key), l.MaxKeyLength)
+	}
+	if l.MaxValueSize > 0 && len(value) > l.MaxValueSize {
+		return fmt.Errorf("value is %w: %d bytes exceeds max_value_size %d", ErrTooLarge, len(value), l.MaxValueSize)
+	}
+	return nil
+}
+
+func (l SizeLimits) CheckRequest(size int) error {
+	if l.MaxRequestSize > 0 && size > l.MaxRequestSize {
+		return fmt.Errorf("request is %w: %d bytes exceeds max_request_size %d", ErrTooLarge, size, l.MaxRequestSize)
+	}
+	return nil
+}
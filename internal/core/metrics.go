@@ -0,0 +1,48 @@
+package core
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	storedKeys = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "kvstore",
+		Subsystem: "store",
+		Name:      "keys",
+		Help:      "Number of keys in store including expired ones which are not deleted yet.",
+	})
+	storedBytes = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "kvstore",
+		Subsystem: "store",
+		Name:      "bytes",
+		Help:      "Total size of keys and values in store.",
+	})
+	expiringKeys = promauto.NewGauge(prometheus.GaugeOpts{
+		Namespace: "kvstore",
+		Subsystem: "store",
+		Name:      "expiring_keys",
+		Help:      "Number of keys with ttl.",
+	})
+	lockWait = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "kvstore",
+		Subsystem: "store",
+		Name:      "lock_wait_seconds",
+		Help:      "Time spent waiting for store lock, mode is either read or write.",
+		Buckets:   prometheus.ExponentialBuckets(0.000_001, 4, 12),
+	}, []string{"mode"})
+	cleanDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "kvstore",
+		Subsystem: "store",
+		Name:      "clean_duration_seconds",
+		Help:      "Duration of cleaning cycle including deletion of expired keys by consumer.",
+		Buckets:   prometheus.DefBuckets,
+	})
+	cleanExpiredKeys = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: "kvstore",
+		Subsystem: "store",
+		Name:      "clean_expired_keys",
+		Help:      "Number of expired keys handed for deletion per cleaning cycle.",
+		Buckets:   prometheus.ExponentialBuckets(1, 4, 8),
+	})
+)
@@ -0,0 +1,178 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+// Quota limits amount of keys and their total size, zero value of a field means no limit
+type Quota struct {
+	MaxKeys  int64
+	MaxBytes int64
+}
+
+type PrefixQuota struct {
+	Prefix string
+	Quota
+}
+
+// QuotasConfig must be the same on every node since quotas are checked when commands are applied
+type QuotasConfig struct {
+	Prefixes []PrefixQuota
+	Users    map[string]Quota
+	// DefaultUser is quota of users which have no own quota
+	DefaultUser Quota
+}
+
+// Usage is amount of keys and total size of keys and values
+type Usage struct {
+	Keys  int64
+	Bytes int64
+}
+
+type UsageEntry struct {
+	Name  string
+	Usage Usage
+	Quota Quota
+}
+
+type UsageReport struct {
+	User     UsageEntry
+	Prefixes []UsageEntry
+}
+
+// quotas tracks usage incrementally, usage is kept for every owner and for configured prefixes only
+type quotas struct {
+	prefixes    []PrefixQuota
+	users       map[string]Quota
+	defaultUser Quota
+
+	prefixUsage map[string]Usage
+	userUsage   map[string]Usage
+}
+
+func newQuotas(conf QuotasConfig) *quotas {
+	return &quotas{
+		prefixes:    conf.Prefixes,
+		users:       conf.Users,
+		defaultUser: conf.DefaultUser,
+		prefixUsage: make(map[string]Usage, len(conf.Prefixes)),
+		userUsage:   make(map[string]Usage),
+	}
+}
+
+func (q *quotas) userQuota(user string) Quota {
+	if quota, ok := q.users[user]; ok {
+		return quota
+	}
+	return q.defaultUser
+}
+
+// check returns error if writing entry in place of old one exceeds some quota, writes which
+// do not increase usage are always allowed, so lowered quota does not block deleting and shrinking
+func (q *quotas) check(key Key, entry, old entryInfo, existed bool) error {
+	keys, bytes := int64(1), entry.size
+	if existed {
+		keys, bytes = 0, entry.size-old.size
+	}
+
+	for _, prefix := range q.prefixes {
+		if !strings.HasPrefix(string(key), prefix.Prefix) {
+			continue
+		}
+
+		if err := exceeds(prefix.Quota, q.prefixUsage[prefix.Prefix], keys, bytes); err != nil {
+			return fmt.Errorf("%w: prefix %q %w", ErrQuotaExceeded, prefix.Prefix, err)
+		}
+	}
+
+	if entry.owner == "" {
+		return nil
+	}
+
+	// ownership of existing key moves to the writer
+	if existed && old.owner != entry.owner {
+		keys, bytes = 1, entry.size
+	}
+
+	if err := exceeds(q.userQuota(entry.owner), q.userUsage[entry.owner], keys, bytes); err != nil {
+		return fmt.Errorf("%w: user %q %w", ErrQuotaExceeded, entry.owner, err)
+	}
+
+	return nil
+}
+
+func (q *quotas) add(key Key, info entryInfo, sign int64) {
+	for _, prefix := range q.prefixes {
+		if strings.HasPrefix(string(key), prefix.Prefix) {
+			q.prefixUsage[prefix.Prefix] = q.prefixUsage[prefix.Prefix].add(sign, info.size)
+		}
+	}
+
+	if info.owner == "" {
+		return
+	}
+
+	usage := q.userUsage[info.owner].add(sign, info.size)
+	if usage.Keys == 0 {
+		delete(q.userUsage, info.owner)
+		return
+	}
+	q.userUsage[info.owner] = usage
+}
+
+func (q *quotas) reset() {
+	clear(q.prefixUsage)
+	clear(q.userUsage)
+}
+
+func (q *quotas) report(user string) UsageReport {
+	report := UsageReport{
+		User: UsageEntry{
+			Name:  user,
+			Usage: q.userUsage[user],
+			Quota: q.userQuota(user),
+		},
+		Prefixes: make([]UsageEntry, 0, len(q.prefixes)),
+	}
+
+	for _, prefix := range q.prefixes {
+		report.Prefixes = append(report.Prefixes, UsageEntry{
+			Name:  prefix.Prefix,
+			Usage: q.prefixUsage[prefix.Prefix],
+			Quota: prefix.Quota,
+		})
+	}
+
+	return report
+}
+
+func (u Usage) add(sign, size int64) Usage {
+	return Usage{
+		Keys:  u.Keys + sign,
+		Bytes: u.Bytes + sign*size,
+	}
+}
+
+func exceeds(quota Quota, usage Usage, keys, bytes int64) error {
+	if keys > 0 && quota.MaxKeys > 0 && usage.Keys+keys > quota.MaxKeys {
+		return fmt.Errorf("allows %d keys", quota.MaxKeys)
+	}
+	if bytes > 0 && quota.MaxBytes > 0 && usage.Bytes+bytes > quota.MaxBytes {
+		return fmt.Errorf("allows %d bytes", quota.MaxBytes)
+	}
+	return nil
+}
+
+// entryInfo is what is needed to account entry in usage
+type entryInfo struct {
+	owner string
+	size  int64
+}
+
+func entrySize(key Key, value Value) int64 {
+	return int64(len(key) + len(value))
+}
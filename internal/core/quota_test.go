@@ -0,0 +1,199 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+)
+
+type write struct {
+	key     Key
+	value   Value
+	owner   string
+	delete  bool
+	wantErr bool
+}
+
+func TestQuotas(t *testing.T) {
+	tests := []struct {
+		name   string
+		quotas QuotasConfig
+		// existing are entries of owner u restored from snapshot, e.g. written before quota was lowered
+		existing map[Key]Value
+		writes   []write
+		user     string
+		want     Usage
+		prefix   string
+		wantPfx  Usage
+	}{
+		{
+			name:   "usage of owner is counted",
+			quotas: QuotasConfig{},
+			writes: []write{
+				{key: "a", value: "1", owner: "u"},
+				{key: "bb", value: "22", owner: "u"},
+				{key: "c", value: "3", owner: "v"},
+			},
+			user: "u",
+			want: Usage{Keys: 2, Bytes: 6},
+		},
+		{
+			name:   "overwrite changes only size",
+			quotas: QuotasConfig{},
+			writes: []write{
+				{key: "a", value: "1", owner: "u"},
+				{key: "a", value: "123", owner: "u"},
+			},
+			user: "u",
+			want: Usage{Keys: 1, Bytes: 4},
+		},
+		{
+			name:   "delete releases usage",
+			quotas: QuotasConfig{},
+			writes: []write{
+				{key: "a", value: "1", owner: "u"},
+				{key: "b", value: "1", owner: "u"},
+				{key: "a", delete: true},
+			},
+			user: "u",
+			want: Usage{Keys: 1, Bytes: 2},
+		},
+		{
+			name:   "user max keys",
+			quotas: QuotasConfig{Users: map[string]Quota{"u": {MaxKeys: 1}}},
+			writes: []write{
+				{key: "a", value: "1", owner: "u"},
+				{key: "b", value: "1", owner: "u", wantErr: true},
+				{key: "a", value: "2", owner: "u"},
+			},
+			user: "u",
+			want: Usage{Keys: 1, Bytes: 2},
+		},
+		{
+			name:   "default user max bytes",
+			quotas: QuotasConfig{DefaultUser: Quota{MaxBytes: 4}},
+			writes: []write{
+				{key: "a", value: "12", owner: "u"},
+				{key: "a", value: "1234", owner: "u", wantErr: true},
+				{key: "a", value: "1", owner: "u"},
+			},
+			user: "u",
+			want: Usage{Keys: 1, Bytes: 2},
+		},
+		{
+			name:     "shrinking is allowed above quota",
+			quotas:   QuotasConfig{DefaultUser: Quota{MaxBytes: 4}},
+			existing: map[Key]Value{"a": "123456"},
+			writes: []write{
+				{key: "a", value: "1234567", owner: "u", wantErr: true},
+				{key: "a", value: "12", owner: "u"},
+				{key: "b", value: "1", owner: "u", wantErr: true},
+			},
+			user: "u",
+			want: Usage{Keys: 1, Bytes: 3},
+		},
+		{
+			name:   "ownership moves to writer",
+			quotas: QuotasConfig{Users: map[string]Quota{"v": {MaxKeys: 1}}},
+			writes: []write{
+				{key: "a", value: "1", owner: "u"},
+				{key: "b", value: "1", owner: "v"},
+				{key: "a", value: "2", owner: "v", wantErr: true},
+				{key: "a", value: "2", owner: "w"},
+			},
+			user: "u",
+			want: Usage{},
+		},
+		{
+			name: "prefix quota applies to every owner",
+			quotas: QuotasConfig{Prefixes: []PrefixQuota{
+				{Prefix: "t/", Quota: Quota{MaxKeys: 2}},
+			}},
+			writes: []write{
+				{key: "t/a", value: "1", owner: "u"},
+				{key: "t/b", value: "1", owner: "v"},
+				{key: "t/c", value: "1", owner: "w", wantErr: true},
+				{key: "x", value: "1", owner: "w"},
+			},
+			user:    "w",
+			want:    Usage{Keys: 1, Bytes: 2},
+			prefix:  "t/",
+			wantPfx: Usage{Keys: 2, Bytes: 8},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			store, err := NewStore(slog.New(slog.DiscardHandler), Config{Quotas: tt.quotas})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if tt.existing != nil {
+				snap := Snapshot{Mp: tt.existing, Owners: make(map[Key]string)}
+				for key := range tt.existing {
+					snap.Owners[key] = "u"
+				}
+				if err := store.Load(ctx, snap); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			for i, w := range tt.writes {
+				if w.delete {
+					if err := store.Delete(ctx, w.key); err != nil {
+						t.Fatal(err)
+					}
+					continue
+				}
+
+				// leader checks quota before proposing and every node checks it again when applying
+				checkErr := store.CheckQuota(ctx, w.key, w.value, w.owner)
+				putErr := store.Put(ctx, w.key, w.value, 0, w.owner)
+				if w.wantErr {
+					if !errors.Is(checkErr, ErrQuotaExceeded) || !errors.Is(putErr, ErrQuotaExceeded) {
+						t.Fatalf("write %d: CheckQuota() error = %v, Put() error = %v, want %v", i, checkErr, putErr, ErrQuotaExceeded)
+					}
+					continue
+				}
+				if checkErr != nil || putErr != nil {
+					t.Fatalf("write %d: CheckQuota() error = %v, Put() error = %v", i, checkErr, putErr)
+				}
+			}
+
+			checkUsage(t, store, tt.user, tt.want, tt.prefix, tt.wantPfx)
+
+			// usage is recomputed from scratch when snapshot is restored
+			snap, err := store.Snapshot(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := store.Load(ctx, snap); err != nil {
+				t.Fatal(err)
+			}
+
+			checkUsage(t, store, tt.user, tt.want, tt.prefix, tt.wantPfx)
+		})
+	}
+}
+
+func checkUsage(t *testing.T, store *Store, user string, want Usage, prefix string, wantPrefix Usage) {
+	t.Helper()
+
+	report, err := store.Usage(context.Background(), user)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.User.Usage != want {
+		t.Fatalf("usage of %s = %+v, want %+v", user, report.User.Usage, want)
+	}
+
+	for _, entry := range report.Prefixes {
+		if entry.Name == prefix && entry.Usage != wantPrefix {
+			t.Fatalf("usage of prefix %s = %+v, want %+v", prefix, entry.Usage, wantPrefix)
+		}
+	}
+}
@@ -7,4 +7,5 @@ import (
 type Snapshot struct {
 	Expirations map[Key]time.Time
 	Mp          map[Key]Value
+	Owners      map[Key]string
 }
@@ -27,11 +27,15 @@ type Config struct {
 	CleanInterval   time.Duration
 	CleanDuration   time.Duration
 	InitialCapacity int64
+	Quotas          QuotasConfig
 }
 
 type Store struct {
 	expirations   map[Key]time.Time
 	mp            map[Key]Value
+	owners        map[Key]string
+	quotas        *quotas
+	bytes         int64
 	mu            *sync.RWMutex
 	logger        *slog.Logger
 	cleanInterval time.Duration
@@ -66,6 +70,8 @@ func NewStore(logger *slog.Logger, conf Config) (*Store, error) {
 	return &Store{
 		expirations:   make(map[Key]time.Time, conf.InitialCapacity),
 		mp:            make(map[Key]Value, conf.InitialCapacity),
+		owners:        make(map[Key]string, conf.InitialCapacity),
+		quotas:        newQuotas(conf.Quotas),
 		mu:            new(sync.RWMutex),
 		logger:        logger,
 		cleanInterval: conf.CleanInterval,
@@ -74,7 +80,7 @@ func NewStore(logger *slog.Logger, conf Config) (*Store, error) {
 }
 
 func (s *Store) Get(_ context.Context, key Key) (*Value, error) {
-	s.mu.RLock()
+	s.rlock()
 	defer s.mu.RUnlock()
 
 	value, ok := s.mp[key]
@@ -90,11 +96,43 @@ func (s *Store) Get(_ context.Context, key Key) (*Value, error) {
 	return &value, nil
 }
 
-func (s *Store) Put(_ context.Context, key Key, value Value, ttl time.Duration) error {
-	s.mu.Lock()
+// CheckQuota returns ErrQuotaExceeded if writing value on behalf of owner exceeds some quota,
+// it lets the leader reject write before it is proposed, Put checks quotas again when write is applied
+func (s *Store) CheckQuota(_ context.Context, key Key, value Value, owner string) error {
+	s.rlock()
+	defer s.mu.RUnlock()
+
+	old, existed := s.info(key)
+
+	return s.quotas.check(key, entryInfo{owner: owner, size: entrySize(key, value)}, old, existed)
+}
+
+// Put writes value on behalf of owner, the write is rejected with ErrQuotaExceeded if it exceeds some quota,
+// usage is built from replicated writes only, so every node rejects the same writes
+func (s *Store) Put(_ context.Context, key Key, value Value, ttl time.Duration, owner string) error {
+	s.lock()
 	defer s.mu.Unlock()
 
+	info := entryInfo{owner: owner, size: entrySize(key, value)}
+
+	old, existed := s.info(key)
+	if err := s.quotas.check(key, info, old, existed); err != nil {
+		return err
+	}
+
+	if existed {
+		s.quotas.add(key, old, -1)
+		s.bytes -= old.size
+	}
+	s.quotas.add(key, info, 1)
+	s.bytes += info.size
+
 	s.mp[key] = value
+	if owner != "" {
+		s.owners[key] = owner
+	} else {
+		delete(s.owners, key)
+	}
 
 	if ttl > 0 {
 		s.expirations[key] = time.Now().Add(ttl)
@@ -102,19 +140,46 @@ func (s *Store) Put(_ context.Context, key Key, value Value, ttl time.Duration)
 		delete(s.expirations, key)
 	}
 
+	s.observe()
+
 	return nil
 }
 
 func (s *Store) Delete(_ context.Context, key Key) error {
-	s.mu.Lock()
+	s.lock()
 	defer s.mu.Unlock()
 
+	if old, existed := s.info(key); existed {
+		s.quotas.add(key, old, -1)
+		s.bytes -= old.size
+	}
+
 	delete(s.mp, key)
 	delete(s.expirations, key)
+	delete(s.owners, key)
+
+	s.observe()
 
 	return nil
 }
 
+// Usage reports usage and quota of user and of every prefix with quota
+func (s *Store) Usage(_ context.Context, user string) (UsageReport, error) {
+	s.rlock()
+	defer s.mu.RUnlock()
+
+	return s.quotas.report(user), nil
+}
+
+func (s *Store) info(key Key) (entryInfo, bool) {
+	value, ok := s.mp[key]
+	if !ok {
+		return entryInfo{}, false
+	}
+
+	return entryInfo{owner: s.owners[key], size: entrySize(key, value)}, true
+}
+
 func (s *Store) Expired(ctx context.Context) <-chan Key {
 	ch := make(chan Key)
 	timer := time.NewTicker(s.cleanInterval)
@@ -139,9 +204,17 @@ func (s *Store) Expired(ctx context.Context) <-chan Key {
 }
 
 func (s *Store) clean(ctx context.Context, res chan<- Key) {
-	s.mu.RLock()
+	start := time.Now()
+
+	s.rlock()
 	defer s.mu.RUnlock()
 
+	var expired int
+	defer func() {
+		cleanDuration.Observe(time.Since(start).Seconds())
+		cleanExpiredKeys.Observe(float64(expired))
+	}()
+
 	ctx, cancel := context.WithTimeout(ctx, s.cleanDuration)
 	defer cancel()
 
@@ -159,28 +232,63 @@ func (s *Store) clean(ctx context.Context, res chan<- Key) {
 		case <-ctx.Done():
 			break
 		case res <- k:
+			expired++
 		}
 	}
 }
 
 func (s *Store) Snapshot(_ context.Context) (Snapshot, error) {
-	s.mu.RLock()
+	s.rlock()
 	defer s.mu.RUnlock()
 
 	snap := Snapshot{
 		Expirations: maps.Clone(s.expirations),
 		Mp:          maps.Clone(s.mp),
+		Owners:      maps.Clone(s.owners),
 	}
 
 	return snap, nil
 }
 
 func (s *Store) Load(_ context.Context, snap Snapshot) error {
-	s.mu.Lock()
+	s.lock()
 	defer s.mu.Unlock()
 
 	s.mp = snap.Mp
 	s.expirations = snap.Expirations
+	s.owners = snap.Owners
+	if s.owners == nil {
+		s.owners = make(map[Key]string)
+	}
+
+	s.quotas.reset()
+	s.bytes = 0
+	for key := range s.mp {
+		info, _ := s.info(key)
+		s.quotas.add(key, info, 1)
+		s.bytes += info.size
+	}
+
+	s.observe()
 
 	return nil
 }
+
+func (s *Store) lock() {
+	start := time.Now()
+	s.mu.Lock()
+	lockWait.WithLabelValues("write").Observe(time.Since(start).Seconds())
+}
+
+func (s *Store) rlock() {
+	start := time.Now()
+	s.mu.RLock()
+	lockWait.WithLabelValues("read").Observe(time.Since(start).Seconds())
+}
+
+// observe updates store gauges, it must be called under write lock
+func (s *Store) observe() {
+	storedKeys.Set(float64(len(s.mp)))
+	storedBytes.Set(float64(s.bytes))
+	expiringKeys.Set(float64(len(s.expirations)))
+}
@@ -0,0 +1,181 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"kvstore/internal/sl"
+	"log/slog"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultInterval = 30 * time.Second
+
+// Resolver is the part of net.Resolver used by discovery, it can be replaced by fake one
+type Resolver interface {
+	LookupHost(ctx context.Context, host string) ([]string, error)
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+}
+
+type Config struct {
+	// Seeds are static addresses of cluster nodes
+	Seeds []string
+	// DNSName is resolved to addresses of cluster nodes, A/AAAA records are used by default
+	DNSName string
+	// DNSPort is used together with addresses from A/AAAA records
+	DNSPort string
+	// SRV enables resolving DNSName as SRV record which contains ports itself
+	SRV      bool
+	Interval time.Duration
+}
+
+// Discovery provides addresses of cluster nodes from static seeds and DNS records
+type Discovery struct {
+	logger   *slog.Logger
+	resolver Resolver
+	seeds    []string
+	dnsName  string
+	dnsPort  string
+	srv      bool
+	interval time.Duration
+	mu       *sync.RWMutex
+	resolved []string
+}
+
+func New(logger *slog.Logger, resolver Resolver, conf Config) (*Discovery, error) {
+	if logger == nil {
+		return nil, errors.New("logger required")
+	}
+
+	logger = logger.With(sl.Component("discovery.Discovery"))
+
+	logger.Debug("creating discovery", sl.Conf(conf))
+
+	if resolver == nil {
+		return nil, errors.New("resolver required")
+	}
+	if conf.DNSName != "" && !conf.SRV && conf.DNSPort == "" {
+		return nil, errors.New("dns port required if srv records are not used")
+	}
+	if conf.Interval <= 0 {
+		conf.Interval = defaultInterval
+	}
+
+	logger.Debug("created successfully", sl.Conf(conf))
+
+	return &Discovery{
+		logger:   logger,
+		resolver: resolver,
+		seeds:    conf.Seeds,
+		dnsName:  conf.DNSName,
+		dnsPort:  conf.DNSPort,
+		srv:      conf.SRV,
+		interval: conf.Interval,
+		mu:       new(sync.RWMutex),
+	}, nil
+}
+
+// Configured reports whether there is any source of cluster nodes
+func (d *Discovery) Configured() bool {
+	return len(d.seeds) > 0 || d.dnsName != ""
+}
+
+// Peers returns static seeds and the latest resolved addresses,
+// DNS name is resolved in place if it has not been resolved yet
+func (d *Discovery) Peers(ctx context.Context) ([]string, error) {
+	d.mu.RLock()
+	resolved := d.resolved
+	d.mu.RUnlock()
+
+	if d.dnsName != "" && len(resolved) == 0 {
+		var err error
+		if resolved, err = d.refresh(ctx); err != nil && len(d.seeds) == 0 {
+			return nil, err
+		}
+	}
+
+	peers := slices.Concat(d.seeds, resolved)
+	if len(peers) == 0 {
+		return nil, errors.New("no peers discovered")
+	}
+
+	return peers, nil
+}
+
+// Run resolves DNS name periodically until context is done
+func (d *Discovery) Run(ctx context.Context) error {
+	if d.dnsName == "" {
+		return nil
+	}
+
+	d.logger.Info("start resolving peers", slog.String("name", d.dnsName), slog.Duration("interval", d.interval))
+
+	ticker := time.NewTicker(d.interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.refresh(ctx); err != nil {
+			d.logger.Warn("cannot resolve peers", sl.Error(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Discovery) refresh(ctx context.Context) ([]string, error) {
+	resolved, err := d.resolve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve %s: %w", d.dnsName, err)
+	}
+
+	slices.Sort(resolved)
+	resolved = slices.Compact(resolved)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if !slices.Equal(d.resolved, resolved) {
+		d.logger.Debug("resolved peers changed", slog.Any("peers", resolved))
+	}
+	d.resolved = resolved
+
+	return resolved, nil
+}
+
+func (d *Discovery) resolve(ctx context.Context) ([]string, error) {
+	if d.srv {
+		_, records, err := d.resolver.LookupSRV(ctx, "", "", d.dnsName)
+		if err != nil {
+			return nil, err
+		}
+
+		addresses := make([]string, 0, len(records))
+		for _, record := range records {
+			host := strings.TrimSuffix(record.Target, ".")
+			addresses = append(addresses, net.JoinHostPort(host, strconv.Itoa(int(record.Port))))
+		}
+
+		return addresses, nil
+	}
+
+	hosts, err := d.resolver.LookupHost(ctx, d.dnsName)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]string, 0, len(hosts))
+	for _, host := range hosts {
+		addresses = append(addresses, net.JoinHostPort(host, d.dnsPort))
+	}
+
+	return addresses, nil
+}
@@ -0,0 +1,123 @@
+package discovery
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"slices"
+	"testing"
+)
+
+type fakeResolver struct {
+	hosts map[string][]string
+	srv   map[string][]*net.SRV
+	err   error
+}
+
+func (r *fakeResolver) LookupHost(_ context.Context, host string) ([]string, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	return r.hosts[host], nil
+}
+
+func (r *fakeResolver) LookupSRV(_ context.Context, _, _, name string) (string, []*net.SRV, error) {
+	if r.err != nil {
+		return "", nil, r.err
+	}
+	return name, r.srv[name], nil
+}
+
+func TestDiscoveryPeers(t *testing.T) {
+	tests := []struct {
+		name     string
+		resolver *fakeResolver
+		conf     Config
+		want     []string
+		wantErr  bool
+	}{
+		{
+			name:     "static seeds",
+			resolver: &fakeResolver{},
+			conf:     Config{Seeds: []string{"node-1:8090", "node-2:8090"}},
+			want:     []string{"node-1:8090", "node-2:8090"},
+		},
+		{
+			name: "a records get dns port and are deduplicated",
+			resolver: &fakeResolver{hosts: map[string][]string{
+				"kvstore.local": {"10.0.0.2", "10.0.0.1", "10.0.0.2"},
+			}},
+			conf: Config{DNSName: "kvstore.local", DNSPort: "8090"},
+			want: []string{"10.0.0.1:8090", "10.0.0.2:8090"},
+		},
+		{
+			name: "srv records carry ports",
+			resolver: &fakeResolver{srv: map[string][]*net.SRV{
+				"_kvstore._tcp.local": {
+					{Target: "node-1.local.", Port: 8090},
+					{Target: "node-2.local.", Port: 8091},
+				},
+			}},
+			conf: Config{DNSName: "_kvstore._tcp.local", SRV: true},
+			want: []string{"node-1.local:8090", "node-2.local:8091"},
+		},
+		{
+			name: "seeds come before resolved addresses",
+			resolver: &fakeResolver{hosts: map[string][]string{
+				"kvstore.local": {"10.0.0.1"},
+			}},
+			conf: Config{Seeds: []string{"seed:8090"}, DNSName: "kvstore.local", DNSPort: "8090"},
+			want: []string{"seed:8090", "10.0.0.1:8090"},
+		},
+		{
+			name:     "seeds are used if dns fails",
+			resolver: &fakeResolver{err: errors.New("no such host")},
+			conf:     Config{Seeds: []string{"seed:8090"}, DNSName: "kvstore.local", DNSPort: "8090"},
+			want:     []string{"seed:8090"},
+		},
+		{
+			name:     "dns failure without seeds",
+			resolver: &fakeResolver{err: errors.New("no such host")},
+			conf:     Config{DNSName: "kvstore.local", DNSPort: "8090"},
+			wantErr:  true,
+		},
+		{
+			name:     "nothing resolved",
+			resolver: &fakeResolver{},
+			conf:     Config{DNSName: "kvstore.local", DNSPort: "8090"},
+			wantErr:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := New(slog.New(slog.NewTextHandler(io.Discard, nil)), tt.resolver, tt.conf)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+
+			got, err := d.Peers(context.Background())
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("Peers() = %v, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Peers() error = %v", err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Fatalf("Peers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewRequiresDNSPort(t *testing.T) {
+	_, err := New(slog.New(slog.NewTextHandler(io.Discard, nil)), &fakeResolver{}, Config{DNSName: "kvstore.local"})
+	if err == nil {
+		t.Fatal("New() without dns port for a records succeeded")
+	}
+}
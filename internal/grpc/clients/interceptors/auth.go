@@ -9,13 +9,36 @@ import (
 
 func NewAuth(username, password string) grpc.UnaryClientInterceptor {
 	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
-		md := metadata.Pairs(
-			internal.UsernameMetaDataKey, username,
-			internal.PasswordMetaDataKey, password,
-		)
+		return invoker(withCredentials(ctx, username, password), method, req, reply, cc, opts...)
+	}
+}
+
+func NewStreamAuth(username, password string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withCredentials(ctx, username, password), desc, cc, method, opts...)
+	}
+}
+
+func withCredentials(ctx context.Context, username, password string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx,
+		internal.UsernameMetaDataKey, username,
+		internal.PasswordMetaDataKey, password,
+	)
+}
 
-		ctx = metadata.NewOutgoingContext(ctx, md)
+// NewClusterAuth authenticates node to other nodes of cluster with cluster secret
+func NewClusterAuth(secret string) grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		return invoker(withClusterSecret(ctx, secret), method, req, reply, cc, opts...)
+	}
+}
 
-		return invoker(ctx, method, req, reply, cc, opts...)
+func NewStreamClusterAuth(secret string) grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		return streamer(withClusterSecret(ctx, secret), desc, cc, method, opts...)
 	}
 }
+
+func withClusterSecret(ctx context.Context, secret string) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, internal.ClusterSecretMetaDataKey, secret)
+}
@@ -2,57 +2,197 @@ package clients
 
 import (
 	"context"
+	"crypto/tls"
 	"errors"
+	"fmt"
 	pb "github.com/HSE-RDBMS-course-work/kvstore-proto/gen/go"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/codes"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"kvstore/internal/grpc/clients/interceptors"
+	"kvstore/internal/grpc/internal"
 	"kvstore/internal/raft"
+	"kvstore/internal/sl"
+	"maps"
+	"sync"
+	"time"
 )
 
 var ErrAddressIsEmpty = errors.New("address is nil")
 
+const attemptTimeout = 5 * time.Second
+
+type peers interface {
+	Configured() bool
+	Peers(ctx context.Context) ([]string, error)
+}
+
 type RaftClientConfig struct {
-	Address  string
 	Username string
-	Password string
+	Password sl.Secret
+	// ClusterSecret is used instead of username and password if it is provided
+	ClusterSecret sl.Secret
 }
 
+// RaftClient joins node to cluster through one of the healthy discovered nodes following leader hints
 type RaftClient struct {
-	client pb.RaftClient
+	peers peers
+	opts  []grpc.DialOption
+	mu    *sync.Mutex
+	conns map[string]*grpc.ClientConn
 }
 
-func NewRaftClient(conf RaftClientConfig) (*RaftClient, error) {
-	if conf.Address == "" {
+// NewRaftClient creates client which uses tls if tls config is provided
+func NewRaftClient(peers peers, tlsConfig *tls.Config, conf RaftClientConfig) (*RaftClient, error) {
+	if peers == nil || !peers.Configured() {
 		return nil, ErrAddressIsEmpty
 	}
-	if conf.Username == "" {
+	if conf.ClusterSecret == "" && conf.Username == "" {
 		return nil, errors.New("username is required")
 	}
-	if conf.Password == "" {
+	if conf.ClusterSecret == "" && conf.Password == "" {
 		return nil, errors.New("password is required")
 	}
 
-	opts := []grpc.DialOption{ //todo
-		grpc.WithTransportCredentials(insecure.NewCredentials()), //todo
-		grpc.WithUnaryInterceptor(interceptors.NewAuth(conf.Username, conf.Password)),
+	unaryAuth := interceptors.NewClusterAuth(string(conf.ClusterSecret))
+	streamAuth := interceptors.NewStreamClusterAuth(string(conf.ClusterSecret))
+	if conf.ClusterSecret == "" {
+		unaryAuth = interceptors.NewAuth(conf.Username, string(conf.Password))
+		streamAuth = interceptors.NewStreamAuth(conf.Username, string(conf.Password))
 	}
 
-	conn, err := grpc.NewClient(conf.Address, opts...)
-	if err != nil {
-		return nil, err
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(internal.TransportCredentials(tlsConfig)),
+		grpc.WithUnaryInterceptor(unaryAuth),
+		grpc.WithStreamInterceptor(streamAuth),
+		grpc.WithStatsHandler(otelgrpc.NewClientHandler(otelgrpc.WithFilter(filters.Not(filters.HealthCheck())))),
 	}
 
 	return &RaftClient{
-		client: pb.NewRaftClient(conn),
+		peers: peers,
+		opts:  opts,
+		mu:    new(sync.Mutex),
+		conns: make(map[string]*grpc.ClientConn),
 	}, nil
 }
 
+// JoinToCluster tries every discovered node once, if node is not a leader
+// the leader it points to is tried before the rest of nodes
 func (rc *RaftClient) JoinToCluster(ctx context.Context, in raft.JoinToClusterIn) error {
-	_, err := rc.client.JoinToCluster(ctx, &pb.JoinIn{
-		JoinerId:      string(in.JoinerAddress),
+	queue, err := rc.peers.Peers(ctx)
+	if err != nil {
+		return fmt.Errorf("cannot discover cluster nodes: %w", err)
+	}
+
+	tried := make(map[string]struct{}, len(queue))
+	peers := make(map[raft.ServerID]raft.ServerAddress)
+
+	var errs []error
+
+	for len(queue) > 0 {
+		address := queue[0]
+		queue = queue[1:]
+
+		if _, ok := tried[address]; ok {
+			continue
+		}
+		tried[address] = struct{}{}
+
+		err := rc.join(ctx, address, in)
+		if err == nil {
+			return nil
+		}
+
+		// credentials and join token are the same for every node, so other nodes reject them too
+		if code := status.Code(err); code == codes.Unauthenticated || code == codes.PermissionDenied {
+			return fmt.Errorf("%s: %w: %w", address, raft.ErrJoinNotAllowed, err)
+		}
+
+		errs = append(errs, fmt.Errorf("%s: %w", address, err))
+
+		if hint, ok := internal.LeaderHint(err); ok {
+			queue = append([]string{hint}, queue...)
+		}
+
+		if known, ok := internal.BootstrapPeers(err); ok {
+			maps.Copy(peers, known)
+		}
+	}
+
+	// peers known by every seed are merged, so joiner can decide whether it bootstraps the cluster
+	if len(peers) > 0 {
+		errs = append(errs, raft.NewErrorNotBootstrapped(peers))
+	}
+
+	return errors.Join(errs...)
+}
+
+func (rc *RaftClient) join(ctx context.Context, address string, in raft.JoinToClusterIn) error {
+	conn, err := rc.conn(address)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, attemptTimeout)
+	defer cancel()
+
+	health, err := healthpb.NewHealthClient(conn).Check(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		return fmt.Errorf("cannot check health: %w", err)
+	}
+	if health.GetStatus() != healthpb.HealthCheckResponse_SERVING {
+		return fmt.Errorf("node is not healthy: %s", health.GetStatus())
+	}
+
+	if in.JoinToken != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, internal.JoinTokenMetaDataKey, in.JoinToken)
+	}
+	if in.JoinerClientAddress != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, internal.ClientAddressMetaDataKey, in.JoinerClientAddress)
+	}
+
+	_, err = pb.NewRaftClient(conn).JoinToCluster(ctx, &pb.JoinIn{
+		JoinerId:      string(in.JoinerID),
 		JoinerAddress: string(in.JoinerAddress),
 	})
 
 	return err
 }
+
+// Close closes connections to every node which was tried
+func (rc *RaftClient) Close() error {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	var errs []error
+	for address, conn := range rc.conns {
+		if err := conn.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", address, err))
+		}
+		delete(rc.conns, address)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (rc *RaftClient) conn(address string) (*grpc.ClientConn, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	if conn, ok := rc.conns[address]; ok {
+		return conn, nil
+	}
+
+	conn, err := grpc.NewClient(address, rc.opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	rc.conns[address] = conn
+
+	return conn, nil
+}
@@ -3,4 +3,12 @@ package internal
 const (
 	UsernameMetaDataKey = "username"
 	PasswordMetaDataKey = "password"
+	// AuthorizationMetaDataKey carries "Bearer <token>"
+	AuthorizationMetaDataKey = "authorization"
+	BearerPrefix             = "Bearer "
+	// ClusterSecretMetaDataKey authenticates nodes of cluster to each other
+	ClusterSecretMetaDataKey = "cluster-secret"
+	JoinTokenMetaDataKey     = "join-token"
+	// ClientAddressMetaDataKey carries client address of joining node, so leader can point clients to it
+	ClientAddressMetaDataKey = "client-address"
 )
@@ -0,0 +1,121 @@
+package internal
+
+import (
+	"errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"kvstore/internal/raft"
+)
+
+const (
+	errorDomain           = "kvstore"
+	reasonIsNotLeader     = "NOT_LEADER"
+	reasonNotBootstrapped = "NOT_BOOTSTRAPPED"
+	leaderAddressInfoKey  = "leader_address"
+	leaderIDInfoKey       = "leader_id"
+)
+
+// NotLeaderError converts raft.ErrorIsNotLeader to grpc status error which carries leader hint in details
+func NotLeaderError(err error) error {
+	st := status.New(codes.FailedPrecondition, err.Error())
+
+	var notLeader *raft.ErrorIsNotLeader
+	if !errors.As(err, &notLeader) {
+		return st.Err()
+	}
+
+	address, id, ok := notLeader.LeaderHint()
+	if !ok {
+		return st.Err()
+	}
+
+	withDetails, detailsErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason: reasonIsNotLeader,
+		Domain: errorDomain,
+		Metadata: map[string]string{
+			leaderAddressInfoKey: string(address),
+			leaderIDInfoKey:      string(id),
+		},
+	})
+	if detailsErr != nil {
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
+
+// LeaderHint extracts client address of the leader from error made by NotLeaderError
+func LeaderHint(err error) (string, bool) {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.FailedPrecondition {
+		return "", false
+	}
+
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.GetReason() != reasonIsNotLeader {
+			continue
+		}
+
+		address := info.GetMetadata()[leaderAddressInfoKey]
+		if address == "" {
+			return "", false
+		}
+
+		return address, true
+	}
+
+	return "", false
+}
+
+// NotBootstrappedError converts raft.ErrorNotBootstrapped to grpc status error which carries
+// known peers in details as raft addresses by their ids
+func NotBootstrappedError(err error) error {
+	st := status.New(codes.Unavailable, err.Error())
+
+	var notBootstrapped *raft.ErrorNotBootstrapped
+	if !errors.As(err, &notBootstrapped) {
+		return st.Err()
+	}
+
+	peers := make(map[string]string)
+	for id, address := range notBootstrapped.Peers() {
+		peers[string(id)] = string(address)
+	}
+
+	withDetails, detailsErr := st.WithDetails(&errdetails.ErrorInfo{
+		Reason:   reasonNotBootstrapped,
+		Domain:   errorDomain,
+		Metadata: peers,
+	})
+	if detailsErr != nil {
+		return st.Err()
+	}
+
+	return withDetails.Err()
+}
+
+// BootstrapPeers extracts peers from error made by NotBootstrappedError
+func BootstrapPeers(err error) (map[raft.ServerID]raft.ServerAddress, bool) {
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.Unavailable {
+		return nil, false
+	}
+
+	for _, detail := range st.Details() {
+		info, ok := detail.(*errdetails.ErrorInfo)
+		if !ok || info.GetReason() != reasonNotBootstrapped {
+			continue
+		}
+
+		peers := make(map[raft.ServerID]raft.ServerAddress, len(info.GetMetadata()))
+		for id, address := range info.GetMetadata() {
+			peers[raft.ServerID(id)] = raft.ServerAddress(address)
+		}
+
+		return peers, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,365 @@
+package servers
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	kvpb "kvstore/gen/go"
+	"kvstore/internal/auth"
+	"kvstore/internal/grpc/internal"
+	"kvstore/internal/raft"
+	"kvstore/internal/ratelimit"
+	"kvstore/internal/sl"
+	"log/slog"
+	"maps"
+	"slices"
+	"time"
+)
+
+const defaultJoinTokenTTL = time.Hour
+
+type adminStore interface {
+	CreateUser(ctx context.Context, user auth.User) error
+	SetPasswordHash(ctx context.Context, name string, hash []byte) error
+	DeleteUser(ctx context.Context, name string) error
+	CreateJoinToken(ctx context.Context, token auth.JoinToken) error
+	SetRateLimit(ctx context.Context, limit ratelimit.UserLimit) error
+	DeleteRateLimit(ctx context.Context, user string) error
+}
+
+type rateLimitsReader interface {
+	Snapshot(ctx context.Context) (map[string]ratelimit.Limit, error)
+}
+
+type usersReader interface {
+	Get(ctx context.Context, name string) (auth.User, error)
+	List(ctx context.Context) ([]auth.User, error)
+}
+
+type logLevels interface {
+	Set(component string, level slog.Level)
+	Reset(component string)
+	List() (slog.Level, map[string]slog.Level)
+}
+
+type clusterEvents interface {
+	Subscribe(ctx context.Context) <-chan raft.Event
+}
+
+type policy interface {
+	Authorize(id auth.Identity, req auth.Request) error
+	Defined(role string) bool
+}
+
+// AdminServer manages users, permissions to call its methods are checked by authorization interceptor
+type AdminServer struct {
+	kvpb.UnimplementedAdminServer
+	store  adminStore
+	users  usersReader
+	limits rateLimitsReader
+	policy policy
+	levels logLevels
+	events clusterEvents
+}
+
+func NewAdminServer(store adminStore, users usersReader, limits rateLimitsReader, policy policy, levels logLevels, events clusterEvents) (*AdminServer, error) {
+	if store == nil {
+		return nil, errors.New("store is required")
+	}
+	if users == nil {
+		return nil, errors.New("users is required")
+	}
+	if limits == nil {
+		return nil, errors.New("limits is required")
+	}
+	if policy == nil {
+		return nil, errors.New("policy is required")
+	}
+	if levels == nil {
+		return nil, errors.New("levels is required")
+	}
+	if events == nil {
+		return nil, errors.New("events is required")
+	}
+
+	return &AdminServer{
+		store:  store,
+		users:  users,
+		limits: limits,
+		policy: policy,
+		levels: levels,
+		events: events,
+	}, nil
+}
+
+func (s *AdminServer) RegisterTo(server *grpc.Server) {
+	kvpb.RegisterAdminServer(server, s)
+}
+
+func (s *AdminServer) CreateUser(ctx context.Context, in *kvpb.CreateUserIn) (*kvpb.CreateUserOut, error) {
+	if in.GetName() == "" {
+		return nil, status.Error(codes.InvalidArgument, "name required")
+	}
+	if in.GetPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "password required")
+	}
+	if err := s.checkRoles(ctx, in.GetRoles()); err != nil {
+		return nil, err
+	}
+
+	hash, err := auth.HashPassword(in.GetPassword())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to hash password")
+	}
+
+	err = s.store.CreateUser(ctx, auth.User{
+		Name:         in.GetName(),
+		PasswordHash: hash,
+		Roles:        in.GetRoles(),
+	})
+	if err != nil {
+		return nil, adminError(err, "failed to create user")
+	}
+
+	return &kvpb.CreateUserOut{}, nil
+}
+
+// checkRoles allows only roles defined by policy, and admin role only to callers which have it,
+// otherwise anyone allowed to create users could create an admin
+func (s *AdminServer) checkRoles(ctx context.Context, roles []string) error {
+	for _, role := range roles {
+		if role == auth.RoleNode {
+			return status.Errorf(codes.InvalidArgument, "role %s is reserved for cluster nodes", auth.RoleNode)
+		}
+		if !s.policy.Defined(role) {
+			return status.Errorf(codes.InvalidArgument, "role %s is not defined", role)
+		}
+	}
+
+	if !slices.Contains(roles, auth.RoleAdmin) {
+		return nil
+	}
+
+	id, ok := auth.IdentityFrom(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "caller is not authenticated")
+	}
+	if !id.HasRole(auth.RoleAdmin) {
+		return status.Errorf(codes.PermissionDenied, "only %s can grant role %s", auth.RoleAdmin, auth.RoleAdmin)
+	}
+
+	return nil
+}
+
+func (s *AdminServer) DeleteUser(ctx context.Context, in *kvpb.DeleteUserIn) (*kvpb.DeleteUserOut, error) {
+	if err := s.store.DeleteUser(ctx, in.GetName()); err != nil {
+		return nil, adminError(err, "failed to delete user")
+	}
+
+	return &kvpb.DeleteUserOut{}, nil
+}
+
+// ChangePassword rotates password of any user for admins and of the caller itself for others
+func (s *AdminServer) ChangePassword(ctx context.Context, in *kvpb.ChangePasswordIn) (*kvpb.ChangePasswordOut, error) {
+	id, ok := auth.IdentityFrom(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "caller is not authenticated")
+	}
+	if id.User != in.GetName() {
+		err := s.policy.Authorize(id, auth.Request{Group: auth.GroupCluster, Permission: auth.PermissionAdmin})
+		if err != nil {
+			return nil, status.Error(codes.PermissionDenied, "only admin can change password of other users")
+		}
+	}
+	if in.GetPassword() == "" {
+		return nil, status.Error(codes.InvalidArgument, "password required")
+	}
+
+	hash, err := auth.HashPassword(in.GetPassword())
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to hash password")
+	}
+
+	if err := s.store.SetPasswordHash(ctx, in.GetName(), hash); err != nil {
+		return nil, adminError(err, "failed to change password")
+	}
+
+	return &kvpb.ChangePasswordOut{}, nil
+}
+
+func (s *AdminServer) ListUsers(ctx context.Context, _ *kvpb.ListUsersIn) (*kvpb.ListUsersOut, error) {
+	users, err := s.users.List(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list users")
+	}
+
+	out := kvpb.ListUsersOut{
+		Users: make([]*kvpb.UserInfo, 0, len(users)),
+	}
+	for _, user := range users {
+		out.Users = append(out.Users, &kvpb.UserInfo{
+			Name:  user.Name,
+			Roles: user.Roles,
+		})
+	}
+
+	return &out, nil
+}
+
+func (s *AdminServer) CreateJoinToken(ctx context.Context, in *kvpb.CreateJoinTokenIn) (*kvpb.CreateJoinTokenOut, error) {
+	ttl := time.Duration(in.GetTtlSeconds()) * time.Second
+	if ttl < 0 {
+		return nil, status.Error(codes.InvalidArgument, "ttl cannot be negative")
+	}
+	if ttl == 0 {
+		ttl = defaultJoinTokenTTL
+	}
+
+	secret, token, err := auth.NewJoinToken(ttl)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to generate join token")
+	}
+
+	if err := s.store.CreateJoinToken(ctx, token); err != nil {
+		return nil, adminError(err, "failed to create join token")
+	}
+
+	return &kvpb.CreateJoinTokenOut{
+		Token:     secret,
+		ExpiresAt: token.ExpiresAt.Unix(),
+	}, nil
+}
+
+func (s *AdminServer) SetRateLimit(ctx context.Context, in *kvpb.SetRateLimitIn) (*kvpb.SetRateLimitOut, error) {
+	limit := in.GetLimit()
+	if limit.GetUser() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user required")
+	}
+	if limit.GetRate() < 0 || limit.GetBurst() < 0 {
+		return nil, status.Error(codes.InvalidArgument, "rate and burst cannot be negative")
+	}
+
+	err := s.store.SetRateLimit(ctx, ratelimit.UserLimit{
+		User: limit.GetUser(),
+		Limit: ratelimit.Limit{
+			Rate:  limit.GetRate(),
+			Burst: int(limit.GetBurst()),
+		},
+	})
+	if err != nil {
+		return nil, adminError(err, "failed to set rate limit")
+	}
+
+	return &kvpb.SetRateLimitOut{}, nil
+}
+
+func (s *AdminServer) DeleteRateLimit(ctx context.Context, in *kvpb.DeleteRateLimitIn) (*kvpb.DeleteRateLimitOut, error) {
+	if err := s.store.DeleteRateLimit(ctx, in.GetUser()); err != nil {
+		return nil, adminError(err, "failed to delete rate limit")
+	}
+
+	return &kvpb.DeleteRateLimitOut{}, nil
+}
+
+func (s *AdminServer) ListRateLimits(ctx context.Context, _ *kvpb.ListRateLimitsIn) (*kvpb.ListRateLimitsOut, error) {
+	limits, err := s.limits.Snapshot(ctx)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to list rate limits")
+	}
+
+	out := kvpb.ListRateLimitsOut{
+		Limits: make([]*kvpb.RateLimit, 0, len(limits)),
+	}
+	for _, user := range slices.Sorted(maps.Keys(limits)) {
+		out.Limits = append(out.Limits, &kvpb.RateLimit{
+			User:  user,
+			Rate:  limits[user].Rate,
+			Burst: int32(limits[user].Burst),
+		})
+	}
+
+	return &out, nil
+}
+
+// SetLogLevel changes level on this node only, it is lost on restart
+func (s *AdminServer) SetLogLevel(_ context.Context, in *kvpb.SetLogLevelIn) (*kvpb.SetLogLevelOut, error) {
+	if in.GetLevel() == "" {
+		if in.GetComponent() == "" {
+			return nil, status.Error(codes.InvalidArgument, "global level cannot be reset")
+		}
+
+		s.levels.Reset(in.GetComponent())
+
+		return &kvpb.SetLogLevelOut{}, nil
+	}
+
+	level, err := sl.ParseLevel(in.GetLevel())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	s.levels.Set(in.GetComponent(), level)
+
+	return &kvpb.SetLogLevelOut{}, nil
+}
+
+func (s *AdminServer) ListLogLevels(_ context.Context, _ *kvpb.ListLogLevelsIn) (*kvpb.ListLogLevelsOut, error) {
+	global, components := s.levels.List()
+
+	out := kvpb.ListLogLevelsOut{
+		Global:     sl.LevelName(global),
+		Components: make([]*kvpb.ComponentLevel, 0, len(components)),
+	}
+	for _, component := range slices.Sorted(maps.Keys(components)) {
+		out.Components = append(out.Components, &kvpb.ComponentLevel{
+			Component: component,
+			Level:     sl.LevelName(components[component]),
+		})
+	}
+
+	return &out, nil
+}
+
+// WatchEvents sends events until client cancels the stream, the stream is closed with unavailable code
+// if node shuts down or client does not keep up with events
+func (s *AdminServer) WatchEvents(_ *kvpb.WatchEventsIn, stream grpc.ServerStreamingServer[kvpb.ClusterEvent]) error {
+	for event := range s.events.Subscribe(stream.Context()) {
+		out := kvpb.ClusterEvent{
+			Type:          event.Type,
+			Time:          event.Time.UnixMilli(),
+			NodeId:        string(event.NodeID),
+			Term:          event.Term,
+			State:         event.State,
+			ServerId:      string(event.ServerID),
+			ServerAddress: string(event.ServerAddress),
+		}
+		if !event.LastContact.IsZero() {
+			out.LastContact = event.LastContact.UnixMilli()
+		}
+
+		if err := stream.Send(&out); err != nil {
+			return err
+		}
+	}
+
+	if err := stream.Context().Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+
+	return status.Error(codes.Unavailable, "event stream is closed, subscribe again")
+}
+
+func adminError(err error, msg string) error {
+	switch {
+	case errors.Is(err, raft.ErrIsNotLeader):
+		return internal.NotLeaderError(err)
+	case errors.Is(err, auth.ErrUserExists):
+		return status.Error(codes.AlreadyExists, err.Error())
+	case errors.Is(err, auth.ErrNoUser):
+		return status.Error(codes.NotFound, err.Error())
+	default:
+		return status.Error(codes.Internal, msg)
+	}
+}
@@ -0,0 +1,63 @@
+package servers
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	kvpb "kvstore/gen/go"
+	"kvstore/internal/auth"
+	"time"
+)
+
+type tokenIssuer interface {
+	Issue(id auth.Identity, scope []string) (string, time.Time, error)
+}
+
+type AuthServer struct {
+	kvpb.UnimplementedAuthServer
+	authenticator authenticator
+	tokens        tokenIssuer
+}
+
+func NewAuthServer(authenticator authenticator, tokens tokenIssuer) (*AuthServer, error) {
+	if authenticator == nil {
+		return nil, errors.New("authenticator is required")
+	}
+	if tokens == nil {
+		return nil, errors.New("tokens is required")
+	}
+
+	return &AuthServer{
+		authenticator: authenticator,
+		tokens:        tokens,
+	}, nil
+}
+
+func (s *AuthServer) RegisterTo(server *grpc.Server) {
+	kvpb.RegisterAuthServer(server, s)
+}
+
+func (s *AuthServer) Authenticate(ctx context.Context, in *kvpb.AuthenticateIn) (*kvpb.AuthenticateOut, error) {
+	id, err := s.authenticator.Authenticate(ctx, in.GetUsername(), in.GetPassword())
+	if errors.Is(err, auth.ErrInvalidCredentials) {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to authenticate")
+	}
+
+	token, expiresAt, err := s.tokens.Issue(id, in.GetScope())
+	if errors.Is(err, auth.ErrTokensDisabled) {
+		return nil, status.Error(codes.FailedPrecondition, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to issue token")
+	}
+
+	return &kvpb.AuthenticateOut{
+		Token:     token,
+		ExpiresAt: expiresAt.Unix(),
+	}, nil
+}
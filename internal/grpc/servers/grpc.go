@@ -1,10 +1,20 @@
 package servers
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
+	pb "github.com/HSE-RDBMS-course-work/kvstore-proto/gen/go"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc/filters"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	kvpb "kvstore/gen/go"
+	"kvstore/internal/audit"
+	"kvstore/internal/auth"
 	"kvstore/internal/grpc/servers/interceptors"
 	"kvstore/internal/sl"
 	"log/slog"
@@ -12,11 +22,46 @@ import (
 	"time"
 )
 
+type authenticator interface {
+	Authenticate(ctx context.Context, username, password string) (auth.Identity, error)
+	AuthenticateNode(secret string) (auth.Identity, error)
+	AuthenticateCertificate(cert *x509.Certificate) (auth.Identity, bool)
+}
+
+type tokenVerifier interface {
+	Verify(token string) (auth.Identity, error)
+}
+
+type limiter interface {
+	Allow(ctx context.Context, caller, user, method string) (time.Duration, bool)
+	AllowAddress(ctx context.Context, address, method string) (time.Duration, bool)
+}
+
+type auditor interface {
+	Record(event audit.Event)
+}
+
+type slowLog interface {
+	Begin(ctx context.Context, operation string) (context.Context, func(err error))
+	Sampled() bool
+	Sample(ctx context.Context, method string, request, response []slog.Attr, err error)
+}
+
+// Dependencies are used by interceptors which every request goes through
+type Dependencies struct {
+	Authenticator authenticator
+	Tokens        tokenVerifier
+	Policy        policy
+	Limiter       limiter
+	Auditor       auditor
+	SlowLog       slowLog
+}
+
 type Config struct {
 	Address           string
-	Username          string
-	Password          string
 	ConnectionTimeout time.Duration
+	// MaxRecvMsgSize rejects larger messages before they are decoded, default of grpc is used if it is zero
+	MaxRecvMsgSize int
 }
 
 type Server struct {
@@ -25,33 +70,97 @@ type Server struct {
 	*grpc.Server
 }
 
-func New(logger *slog.Logger, conf Config) (*Server, error) {
+// New creates grpc server which accepts only tls connections if tls config is provided
+func New(logger *slog.Logger, deps Dependencies, tlsConfig *tls.Config, conf Config) (*Server, error) {
 	if logger == nil {
 		return nil, errors.New("logger required")
 	}
-	if conf.Address == "" {
-		return nil, errors.New("address required")
+	if deps.Authenticator == nil {
+		return nil, errors.New("authenticator required")
+	}
+	if deps.Tokens == nil {
+		return nil, errors.New("tokens required")
+	}
+	if deps.Policy == nil {
+		return nil, errors.New("policy required")
 	}
-	if conf.Username == "" {
-		return nil, errors.New("username required")
+	if deps.Limiter == nil {
+		return nil, errors.New("limiter required")
 	}
-	if conf.Password == "" {
-		return nil, errors.New("password required")
+	if deps.Auditor == nil {
+		return nil, errors.New("auditor required")
+	}
+	if deps.SlowLog == nil {
+		return nil, errors.New("slow log required")
+	}
+	if conf.Address == "" {
+		return nil, errors.New("address required")
 	}
 	if conf.ConnectionTimeout <= 0 {
 		conf.ConnectionTimeout = 0
 	}
 
-	server := grpc.NewServer(
+	noAuthMethods := []string{
+		healthpb.Health_Check_FullMethodName,
+		healthpb.Health_Watch_FullMethodName,
+		kvpb.Auth_Authenticate_FullMethodName,
+	}
+
+	// raft transport calls are made several times per second by every peer
+	noLogServices := []string{
+		kvpb.RaftTransport_ServiceDesc.ServiceName,
+	}
+
+	noLimitMethods := []string{
+		healthpb.Health_Check_FullMethodName,
+		healthpb.Health_Watch_FullMethodName,
+	}
+
+	// peers share few addresses and may reach them without cluster secret or node certificate,
+	// so their raft traffic is limited only after authentication
+	noAddressLimitMethods := append([]string{
+		pb.Raft_JoinToCluster_FullMethodName,
+		kvpb.RaftTransport_AppendEntries_FullMethodName,
+		kvpb.RaftTransport_RequestVote_FullMethodName,
+		kvpb.RaftTransport_RequestPreVote_FullMethodName,
+		kvpb.RaftTransport_TimeoutNow_FullMethodName,
+		kvpb.RaftTransport_InstallSnapshot_FullMethodName,
+	}, noLimitMethods...)
+
+	opts := []grpc.ServerOption{
 		grpc.ChainUnaryInterceptor(
 			interceptors.NewRecovery(logger),
-			interceptors.NewLogging(logger),
-			interceptors.NewAuth(conf.Username, conf.Password, []string{
-				healthpb.Health_Check_FullMethodName,
-			}),
+			interceptors.NewSlowLog(deps.SlowLog, sampledMethods),
+			interceptors.NewLogging(logger, noLogServices),
+			interceptors.NewAddressRateLimit(deps.Limiter, deps.Authenticator, noAddressLimitMethods),
+			interceptors.NewAuth(deps.Authenticator, deps.Tokens, noAuthMethods),
+			interceptors.NewRateLimit(deps.Limiter, noLimitMethods),
+			interceptors.NewAudit(deps.Auditor, auditedMethods),
+			interceptors.NewAuthorization(logger, deps.Policy, methodRules, noAuthMethods),
+		),
+		grpc.ChainStreamInterceptor(
+			interceptors.NewStreamRecovery(logger),
+			interceptors.NewStreamLogging(logger, noLogServices),
+			interceptors.NewStreamAddressRateLimit(deps.Limiter, deps.Authenticator, noAddressLimitMethods),
+			interceptors.NewStreamAuth(deps.Authenticator, deps.Tokens, noAuthMethods),
+			interceptors.NewStreamRateLimit(deps.Limiter, noLimitMethods),
+			interceptors.NewStreamAuthorization(logger, deps.Policy, methodRules, noAuthMethods),
 		),
 		grpc.ConnectionTimeout(conf.ConnectionTimeout),
-	)
+		// trace context is extracted from every request except health checks and raft transport ones
+		grpc.StatsHandler(otelgrpc.NewServerHandler(otelgrpc.WithFilter(filters.None(
+			filters.HealthCheck(),
+			filters.ServiceName(kvpb.RaftTransport_ServiceDesc.ServiceName),
+		)))),
+	}
+	if tlsConfig != nil {
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsConfig)))
+	}
+	if conf.MaxRecvMsgSize > 0 {
+		opts = append(opts, grpc.MaxRecvMsgSize(conf.MaxRecvMsgSize))
+	}
+
+	server := grpc.NewServer(opts...)
 
 	listener, err := net.Listen("tcp", conf.Address)
 	if err != nil {
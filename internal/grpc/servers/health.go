@@ -1,21 +1,112 @@
 package servers
 
 import (
+	"context"
+	"errors"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/health"
 	pb "google.golang.org/grpc/health/grpc_health_v1"
+	"kvstore/internal/raft"
+	"kvstore/internal/sl"
+	"log/slog"
+	"time"
 )
 
+const (
+	// HealthWrite is serving when leader is known, so writes can be proposed
+	HealthWrite = "kvstore.write"
+	// HealthRead is serving when FSM has applied every committed log after restore
+	HealthRead = "kvstore.read"
+
+	healthPollInterval = time.Second
+)
+
+type raftNode interface {
+	Status() raft.Status
+	Changes(ctx context.Context) <-chan struct{}
+}
+
+// HealthServer reports statuses of services driven by raft state,
+// the overall "" service is always serving while server is up because nodes check it to join
 type HealthServer struct {
 	*health.Server
+	logger   *slog.Logger
+	node     raftNode
+	write    bool
+	read     bool
+	caughtUp bool
 }
 
-func NewHealthServer() *HealthServer {
-	return &HealthServer{
-		Server: health.NewServer(),
+func NewHealthServer(logger *slog.Logger, node raftNode) (*HealthServer, error) {
+	if logger == nil {
+		return nil, errors.New("logger required")
+	}
+
+	logger = logger.With(sl.Component("grpc.HealthServer"))
+
+	if node == nil {
+		return nil, errors.New("node required")
 	}
+
+	server := health.NewServer()
+	server.SetServingStatus(HealthWrite, pb.HealthCheckResponse_NOT_SERVING)
+	server.SetServingStatus(HealthRead, pb.HealthCheckResponse_NOT_SERVING)
+
+	logger.Debug("created successfully")
+
+	return &HealthServer{
+		Server: server,
+		logger: logger,
+		node:   node,
+	}, nil
 }
 
 func (s *HealthServer) RegisterTo(server *grpc.Server) {
 	pb.RegisterHealthServer(server, s)
 }
+
+// Run updates statuses on raft changes, applied index is polled since raft has no event for it
+func (s *HealthServer) Run(ctx context.Context) error {
+	changes := s.node.Changes(ctx)
+
+	ticker := time.NewTicker(healthPollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.update()
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-changes:
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *HealthServer) update() {
+	status := s.node.Status()
+
+	if !s.caughtUp && status.CommitIndex > 0 && status.AppliedIndex >= status.CommitIndex {
+		s.caughtUp = true
+	}
+
+	s.set(HealthWrite, &s.write, status.LeaderAddress != "" && status.State != raft.StateShutdown)
+	s.set(HealthRead, &s.read, s.caughtUp && status.State != raft.StateShutdown)
+}
+
+func (s *HealthServer) set(service string, current *bool, serving bool) {
+	if *current == serving {
+		return
+	}
+	*current = serving
+
+	status := pb.HealthCheckResponse_NOT_SERVING
+	if serving {
+		status = pb.HealthCheckResponse_SERVING
+	}
+
+	s.SetServingStatus(service, status)
+
+	s.logger.Info("health status changed", slog.String("service", service), slog.String("status", status.String()))
+}
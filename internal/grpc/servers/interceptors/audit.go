@@ -0,0 +1,87 @@
+package interceptors
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"kvstore/internal/audit"
+	"kvstore/internal/auth"
+	"time"
+)
+
+// AuditKeys extracts keys, user names or node ids which request touches
+type AuditKeys func(in any) []string
+
+type auditor interface {
+	Record(event audit.Event)
+}
+
+type valued interface {
+	GetValue() string
+}
+
+// NewAudit records result of every method from methods, it should go before authorization,
+// so denied requests are recorded too
+func NewAudit(auditor auditor, methods map[string]AuditKeys) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, in any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		keys, ok := methods[info.FullMethod]
+		if !ok {
+			return handler(ctx, in)
+		}
+
+		ctx = audit.WithActions(audit.WithIndex(ctx))
+		start := time.Now()
+
+		out, err := handler(ctx, in)
+
+		event := audit.Event{
+			Time:   start,
+			Peer:   peerAddress(ctx),
+			Method: info.FullMethod,
+			Keys:   keys(in),
+			Result: status.Code(err).String(),
+			Index:  audit.IndexFrom(ctx),
+		}
+		if id, ok := auth.IdentityFrom(ctx); ok {
+			event.User = id.User
+		}
+		if req, ok := in.(valued); ok {
+			event.Value = req.GetValue()
+		}
+		event.Error = errorMessage(err)
+
+		auditor.Record(event)
+
+		// actions are recorded as separate events on behalf of the same caller
+		for _, action := range audit.ActionsFrom(ctx) {
+			auditor.Record(audit.Event{
+				Time:   start,
+				User:   event.User,
+				Peer:   event.Peer,
+				Method: action.Method,
+				Keys:   action.Keys,
+				Result: status.Code(action.Err).String(),
+				Error:  errorMessage(action.Err),
+				Index:  action.Index,
+			})
+		}
+
+		return out, err
+	}
+}
+
+func errorMessage(err error) string {
+	if err == nil {
+		return ""
+	}
+	return status.Convert(err).Message()
+}
+
+func peerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+	return p.Addr.String()
+}
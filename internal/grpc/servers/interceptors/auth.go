@@ -2,43 +2,146 @@ package interceptors
 
 import (
 	"context"
+	"crypto/x509"
+	"errors"
+	middleware "github.com/grpc-ecosystem/go-grpc-middleware/v2"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
 	"google.golang.org/grpc/status"
+	"kvstore/internal/auth"
 	"kvstore/internal/grpc/internal"
+	"kvstore/internal/slowlog"
+	"strings"
 )
 
-func NewAuth(username, password string, noAuthMethods []string) grpc.UnaryServerInterceptor {
-	ignore := make(map[string]struct{}, len(noAuthMethods))
-	for _, method := range noAuthMethods {
-		ignore[method] = struct{}{}
-	}
+type authenticator interface {
+	Authenticate(ctx context.Context, username, password string) (auth.Identity, error)
+	AuthenticateNode(secret string) (auth.Identity, error)
+	AuthenticateCertificate(cert *x509.Certificate) (auth.Identity, bool)
+}
+
+type tokenVerifier interface {
+	Verify(token string) (auth.Identity, error)
+}
+
+// NewAuth accepts node certificate, cluster secret, bearer token or username and password
+func NewAuth(authenticator authenticator, tokens tokenVerifier, noAuthMethods []string) grpc.UnaryServerInterceptor {
+	ignore := ignored(noAuthMethods)
 
 	return func(ctx context.Context, in any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
 		if _, ok := ignore[info.FullMethod]; ok {
 			return handler(ctx, in)
 		}
 
-		md, ok := metadata.FromIncomingContext(ctx)
-		if !ok {
-			return nil, status.Error(codes.Unauthenticated, "missing metadata")
+		done := slowlog.Phase(ctx, slowlog.PhaseAuth)
+		ctx, err := authenticate(ctx, authenticator, tokens)
+		done()
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, in)
+	}
+}
+
+func NewStreamAuth(authenticator authenticator, tokens tokenVerifier, noAuthMethods []string) grpc.StreamServerInterceptor {
+	ignore := ignored(noAuthMethods)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, ok := ignore[info.FullMethod]; ok {
+			return handler(srv, ss)
 		}
 
-		incomingUsername := md[internal.UsernameMetaDataKey]
-		if len(incomingUsername) == 0 {
-			return nil, status.Error(codes.Unauthenticated, "username missing")
+		ctx, err := authenticate(ss.Context(), authenticator, tokens)
+		if err != nil {
+			return err
 		}
 
-		incomingPassword := md[internal.PasswordMetaDataKey]
-		if len(incomingPassword) == 0 {
-			return nil, status.Error(codes.Unauthenticated, "password missing")
+		wrapped := middleware.WrapServerStream(ss)
+		wrapped.WrappedContext = ctx
+
+		return handler(srv, wrapped)
+	}
+}
+
+// authenticate checks client certificate or credentials from metadata and puts identity of the caller to context
+func authenticate(ctx context.Context, authenticator authenticator, tokens tokenVerifier) (context.Context, error) {
+	if cert := verifiedCertificate(ctx); cert != nil {
+		if id, ok := authenticator.AuthenticateCertificate(cert); ok {
+			return auth.WithIdentity(ctx, id), nil
 		}
+	}
+
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "missing metadata")
+	}
 
-		if incomingUsername[0] != username || incomingPassword[0] != password {
-			return nil, status.Error(codes.Unauthenticated, "username or password mismatch")
+	if secret := md[internal.ClusterSecretMetaDataKey]; len(secret) > 0 {
+		id, err := authenticator.AuthenticateNode(secret[0])
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, "cluster secret mismatch")
 		}
 
-		return handler(ctx, in)
+		return auth.WithIdentity(ctx, id), nil
+	}
+
+	if authorization := md[internal.AuthorizationMetaDataKey]; len(authorization) > 0 {
+		token, ok := strings.CutPrefix(authorization[0], internal.BearerPrefix)
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "bearer token expected")
+		}
+
+		id, err := tokens.Verify(token)
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+
+		return auth.WithIdentity(ctx, id), nil
+	}
+
+	incomingUsername := md[internal.UsernameMetaDataKey]
+	if len(incomingUsername) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "username missing")
+	}
+
+	incomingPassword := md[internal.PasswordMetaDataKey]
+	if len(incomingPassword) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "password missing")
+	}
+
+	id, err := authenticator.Authenticate(ctx, incomingUsername[0], incomingPassword[0])
+	if errors.Is(err, auth.ErrInvalidCredentials) {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to authenticate")
+	}
+
+	return auth.WithIdentity(ctx, id), nil
+}
+
+func verifiedCertificate(ctx context.Context) *x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.VerifiedChains) == 0 || len(info.State.VerifiedChains[0]) == 0 {
+		return nil
+	}
+
+	return info.State.VerifiedChains[0][0]
+}
+
+func ignored(methods []string) map[string]struct{} {
+	ignore := make(map[string]struct{}, len(methods))
+	for _, method := range methods {
+		ignore[method] = struct{}{}
 	}
+	return ignore
 }
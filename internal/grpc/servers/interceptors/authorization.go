@@ -0,0 +1,105 @@
+package interceptors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"kvstore/internal/auth"
+	"kvstore/internal/sl"
+	"log/slog"
+)
+
+// MethodRule is a permission required to call method, only authentication is required if group is empty
+type MethodRule struct {
+	Group      auth.Group
+	Permission auth.Permission
+}
+
+type policy interface {
+	Authorize(id auth.Identity, req auth.Request) error
+}
+
+type keyed interface {
+	GetKey() string
+}
+
+// NewAuthorization checks permissions of the caller authenticated by auth interceptor,
+// methods which are not in rules are denied
+func NewAuthorization(logger *slog.Logger, policy policy, rules map[string]MethodRule, noAuthMethods []string) grpc.UnaryServerInterceptor {
+	auditLogger := logger.With(sl.Component("audit"))
+	ignore := ignored(noAuthMethods)
+
+	return func(ctx context.Context, in any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if _, ok := ignore[info.FullMethod]; ok {
+			return handler(ctx, in)
+		}
+
+		var keys []string
+		if req, ok := in.(keyed); ok {
+			keys = []string{req.GetKey()}
+		}
+
+		if err := authorize(ctx, auditLogger, policy, rules, info.FullMethod, keys); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, in)
+	}
+}
+
+func NewStreamAuthorization(logger *slog.Logger, policy policy, rules map[string]MethodRule, noAuthMethods []string) grpc.StreamServerInterceptor {
+	auditLogger := logger.With(sl.Component("audit"))
+	ignore := ignored(noAuthMethods)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, ok := ignore[info.FullMethod]; ok {
+			return handler(srv, ss)
+		}
+
+		if err := authorize(ss.Context(), auditLogger, policy, rules, info.FullMethod, nil); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func authorize(ctx context.Context, auditLogger *slog.Logger, policy policy, rules map[string]MethodRule, method string, keys []string) error {
+	id, ok := auth.IdentityFrom(ctx)
+	if !ok {
+		return status.Error(codes.Unauthenticated, "caller is not authenticated")
+	}
+
+	var err error
+	switch rule, ok := rules[method]; {
+	case !ok:
+		err = fmt.Errorf("%w: method is not allowed", auth.ErrPermissionDenied)
+	case rule.Group == "":
+		return nil
+	default:
+		err = policy.Authorize(id, auth.Request{
+			Group:      rule.Group,
+			Permission: rule.Permission,
+			Keys:       keys,
+		})
+	}
+	if err == nil {
+		return nil
+	}
+
+	auditLogger.WarnContext(ctx, "permission denied",
+		slog.String("user", id.User),
+		slog.String("method", method),
+		slog.Any("keys", keys),
+		sl.Error(err),
+	)
+
+	if errors.Is(err, auth.ErrPermissionDenied) {
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+
+	return status.Error(codes.Internal, "failed to authorize")
+}
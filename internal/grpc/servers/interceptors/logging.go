@@ -2,7 +2,9 @@ package interceptors
 
 import (
 	"context"
+	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors"
 	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/logging"
+	"github.com/grpc-ecosystem/go-grpc-middleware/v2/interceptors/selector"
 	"google.golang.org/grpc"
 	"log/slog"
 )
@@ -12,11 +14,28 @@ type slogWrapper struct {
 }
 
 func (sw *slogWrapper) Log(ctx context.Context, level logging.Level, msg string, fields ...any) {
-	sw.logger.Log(ctx, slog.Level(level), msg, fields)
+	sw.logger.Log(ctx, slog.Level(level), msg, fields...)
 }
 
-func NewLogging(logger *slog.Logger) grpc.UnaryServerInterceptor {
-	return logging.UnaryServerInterceptor(&slogWrapper{
+// NewLogging logs start and finish of calls except calls of noLogServices,
+// e.g. raft transport which sends heartbeats several times per second
+func NewLogging(logger *slog.Logger, noLogServices []string) grpc.UnaryServerInterceptor {
+	return selector.UnaryServerInterceptor(logging.UnaryServerInterceptor(&slogWrapper{
 		logger: logger,
+	}), logged(noLogServices))
+}
+
+func NewStreamLogging(logger *slog.Logger, noLogServices []string) grpc.StreamServerInterceptor {
+	return selector.StreamServerInterceptor(logging.StreamServerInterceptor(&slogWrapper{
+		logger: logger,
+	}), logged(noLogServices))
+}
+
+func logged(noLogServices []string) selector.Matcher {
+	ignore := ignored(noLogServices)
+
+	return selector.MatchFunc(func(_ context.Context, callMeta interceptors.CallMeta) bool {
+		_, ok := ignore[callMeta.Service]
+		return !ok
 	})
 }
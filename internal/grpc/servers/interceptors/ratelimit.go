@@ -0,0 +1,168 @@
+package interceptors
+
+import (
+	"context"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"kvstore/internal/auth"
+	"kvstore/internal/grpc/internal"
+	"math"
+	"net"
+	"strconv"
+	"time"
+)
+
+const retryAfterMetaDataKey = "retry-after"
+
+type limiter interface {
+	Allow(ctx context.Context, caller, user, method string) (time.Duration, bool)
+	AllowAddress(ctx context.Context, address, method string) (time.Duration, bool)
+}
+
+// NewAddressRateLimit limits requests by client address before authentication, other cluster nodes
+// are recognized by certificate or cluster secret which are cheap to check and are not limited
+func NewAddressRateLimit(limiter limiter, authenticator authenticator, noLimitMethods []string) grpc.UnaryServerInterceptor {
+	ignore := ignored(noLimitMethods)
+
+	return func(ctx context.Context, in any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if _, ok := ignore[info.FullMethod]; ok {
+			return handler(ctx, in)
+		}
+
+		if err := limitAddress(ctx, limiter, authenticator, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, in)
+	}
+}
+
+func NewStreamAddressRateLimit(limiter limiter, authenticator authenticator, noLimitMethods []string) grpc.StreamServerInterceptor {
+	ignore := ignored(noLimitMethods)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, ok := ignore[info.FullMethod]; ok {
+			return handler(srv, ss)
+		}
+
+		if err := limitAddress(ss.Context(), limiter, authenticator, info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+// NewRateLimit limits requests of authenticated users and of unauthenticated callers by their addresses,
+// requests of other cluster nodes are not limited
+func NewRateLimit(limiter limiter, noLimitMethods []string) grpc.UnaryServerInterceptor {
+	ignore := ignored(noLimitMethods)
+
+	return func(ctx context.Context, in any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if _, ok := ignore[info.FullMethod]; ok {
+			return handler(ctx, in)
+		}
+
+		if err := limit(ctx, limiter, info.FullMethod); err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, in)
+	}
+}
+
+func NewStreamRateLimit(limiter limiter, noLimitMethods []string) grpc.StreamServerInterceptor {
+	ignore := ignored(noLimitMethods)
+
+	return func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if _, ok := ignore[info.FullMethod]; ok {
+			return handler(srv, ss)
+		}
+
+		if err := limit(ss.Context(), limiter, info.FullMethod); err != nil {
+			return err
+		}
+
+		return handler(srv, ss)
+	}
+}
+
+func limit(ctx context.Context, limiter limiter, method string) error {
+	id, authenticated := auth.IdentityFrom(ctx)
+	if authenticated && id.Node {
+		return nil
+	}
+
+	caller, user := "user:"+id.User, id.User
+	if !authenticated {
+		caller, user = "address:"+peerHost(ctx), ""
+	}
+
+	retryAfter, ok := limiter.Allow(ctx, caller, user, method)
+	if ok {
+		return nil
+	}
+
+	return exhausted(ctx, retryAfter)
+}
+
+func limitAddress(ctx context.Context, limiter limiter, authenticator authenticator, method string) error {
+	if isNode(ctx, authenticator) {
+		return nil
+	}
+
+	retryAfter, ok := limiter.AllowAddress(ctx, peerHost(ctx), method)
+	if ok {
+		return nil
+	}
+
+	return exhausted(ctx, retryAfter)
+}
+
+// isNode checks only credentials of nodes, so it does not run bcrypt for passwords of users
+func isNode(ctx context.Context, authenticator authenticator) bool {
+	if cert := verifiedCertificate(ctx); cert != nil {
+		if _, ok := authenticator.AuthenticateCertificate(cert); ok {
+			return true
+		}
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if secret := md.Get(internal.ClusterSecretMetaDataKey); len(secret) > 0 {
+		_, err := authenticator.AuthenticateNode(secret[0])
+		return err == nil
+	}
+
+	return false
+}
+
+func exhausted(ctx context.Context, retryAfter time.Duration) error {
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	_ = grpc.SetHeader(ctx, metadata.Pairs(retryAfterMetaDataKey, strconv.Itoa(seconds)))
+
+	st := status.New(codes.ResourceExhausted, "rate limit exceeded")
+	if withDetails, err := st.WithDetails(&errdetails.RetryInfo{RetryDelay: durationpb.New(retryAfter)}); err == nil {
+		st = withDetails
+	}
+
+	return st.Err()
+}
+
+func peerHost(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return ""
+	}
+
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+
+	return host
+}
@@ -12,17 +12,25 @@ import (
 )
 
 func NewRecovery(logger *slog.Logger) grpc.UnaryServerInterceptor {
+	return recovery.UnaryServerInterceptor(
+		recovery.WithRecoveryHandlerContext(recoveryHandler(logger)),
+	)
+}
+
+func NewStreamRecovery(logger *slog.Logger) grpc.StreamServerInterceptor {
+	return recovery.StreamServerInterceptor(
+		recovery.WithRecoveryHandlerContext(recoveryHandler(logger)),
+	)
+}
+
+func recoveryHandler(logger *slog.Logger) recovery.RecoveryHandlerFuncContext {
 	recoveryLogger := logger.With(sl.Component("grpc.Recovery"))
 
-	recoveryFunc := func(ctx context.Context, p any) (err error) {
+	return func(ctx context.Context, p any) (err error) {
 		recoveryLogger.ErrorContext(ctx, "panic while handling grpc request",
 			sl.Panic(p),
 			slog.String("trace", string(debug.Stack())),
 		)
 		return status.Error(codes.Internal, "internal servers error")
 	}
-
-	return recovery.UnaryServerInterceptor(
-		recovery.WithRecoveryHandlerContext(recoveryFunc),
-	)
 }
@@ -0,0 +1,36 @@
+package interceptors
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"log/slog"
+)
+
+// SamplePayload describes request and response of method, keys and values must be added with sl.Key
+// and sl.Value, so they are redacted
+type SamplePayload func(in, out any) (request, response []slog.Attr)
+
+type slowLog interface {
+	Begin(ctx context.Context, operation string) (context.Context, func(err error))
+	Sampled() bool
+	Sample(ctx context.Context, method string, request, response []slog.Attr, err error)
+}
+
+// NewSlowLog times requests and samples payloads of methods, payloads of other methods
+// are never logged since they may contain credentials
+func NewSlowLog(slow slowLog, methods map[string]SamplePayload) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, in any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, done := slow.Begin(ctx, info.FullMethod)
+
+		out, err := handler(ctx, in)
+
+		done(err)
+
+		if payload, ok := methods[info.FullMethod]; ok && slow.Sampled() {
+			request, response := payload(in, out)
+			slow.Sample(ctx, info.FullMethod, request, response, err)
+		}
+
+		return out, err
+	}
+}
@@ -7,7 +7,9 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 	"kvstore/internal/core"
+	"kvstore/internal/grpc/internal"
 	"kvstore/internal/raft"
 	"time"
 )
@@ -24,15 +26,17 @@ type kvstore interface {
 type KVStoreServer struct {
 	pb.UnimplementedKVStoreServer
 	store kvstore
+	sizes core.SizeLimits
 }
 
-func NewKVStoreServer(store kvstore) (*KVStoreServer, error) {
+func NewKVStoreServer(store kvstore, sizes core.SizeLimits) (*KVStoreServer, error) {
 	if store == nil {
 		return nil, errors.New("store is required")
 	}
 
 	return &KVStoreServer{
 		store: store,
+		sizes: sizes,
 	}, nil
 }
 
@@ -40,22 +44,38 @@ func (s *KVStoreServer) RegisterTo(server *grpc.Server) {
 	pb.RegisterKVStoreServer(server, s)
 }
 
-func (s *KVStoreServer) Get(ctx context.Context, in *pb.GetIn) (*pb.GetOut, error) {
+func (s *KVStoreServer) Get(ctx context.Context, in *pb.GetIn) (_ *pb.GetOut, err error) {
+	defer observe("Get", time.Now(), &err)
+
 	return s.get(ctx, in, s.store.Get)
 }
 
-func (s *KVStoreServer) ConsistentGet(ctx context.Context, in *pb.GetIn) (*pb.GetOut, error) {
+func (s *KVStoreServer) ConsistentGet(ctx context.Context, in *pb.GetIn) (_ *pb.GetOut, err error) {
+	defer observe("ConsistentGet", time.Now(), &err)
+
 	return s.get(ctx, in, s.store.ConsistentGet)
 }
 
-func (s *KVStoreServer) Put(ctx context.Context, in *pb.PutIn) (*pb.PutOut, error) {
+func (s *KVStoreServer) Put(ctx context.Context, in *pb.PutIn) (_ *pb.PutOut, err error) {
+	defer observe("Put", time.Now(), &err)
+
+	if err := s.sizes.CheckRequest(proto.Size(in)); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
 	key := core.Key(in.GetKey())
 	value := core.Value(in.GetValue())
 	ttl := time.Duration(in.GetTtl())
 
-	err := s.store.Put(ctx, key, value, ttl)
+	err = s.store.Put(ctx, key, value, ttl)
 	if errors.Is(err, raft.ErrIsNotLeader) {
-		return nil, status.Error(codes.FailedPrecondition, err.Error())
+		return nil, internal.NotLeaderError(err)
+	}
+	if errors.Is(err, core.ErrTooLarge) {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, core.ErrQuotaExceeded) {
+		return nil, status.Error(codes.ResourceExhausted, err.Error())
 	}
 	if err != nil {
 		return nil, status.Error(codes.Internal, "failed to put")
@@ -64,12 +84,14 @@ func (s *KVStoreServer) Put(ctx context.Context, in *pb.PutIn) (*pb.PutOut, erro
 	return &pb.PutOut{}, nil
 }
 
-func (s *KVStoreServer) Delete(ctx context.Context, in *pb.DeleteIn) (*pb.DeleteOut, error) {
+func (s *KVStoreServer) Delete(ctx context.Context, in *pb.DeleteIn) (_ *pb.DeleteOut, err error) {
+	defer observe("Delete", time.Now(), &err)
+
 	key := core.Key(in.GetKey())
 
-	err := s.store.Delete(ctx, key)
+	err = s.store.Delete(ctx, key)
 	if errors.Is(err, raft.ErrIsNotLeader) {
-		return nil, status.Error(codes.FailedPrecondition, err.Error())
+		return nil, internal.NotLeaderError(err)
 	}
 	if err != nil {
 		return nil, status.Error(codes.Internal, "failed to delete")
@@ -83,7 +105,7 @@ func (s *KVStoreServer) get(ctx context.Context, in *pb.GetIn, get getFn) (*pb.G
 
 	value, err := get(ctx, key)
 	if errors.Is(err, raft.ErrIsNotLeader) {
-		return nil, status.Error(codes.FailedPrecondition, err.Error())
+		return nil, internal.NotLeaderError(err)
 	}
 	if errors.Is(err, core.ErrNoKey) {
 		return nil, status.Errorf(codes.NotFound, "there is no %s", key)
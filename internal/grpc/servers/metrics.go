@@ -0,0 +1,30 @@
+package servers
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+var (
+	kvstoreRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "kvstore",
+		Subsystem: "server",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of kvstore requests handling.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method"})
+	kvstoreRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kvstore",
+		Subsystem: "server",
+		Name:      "requests_total",
+		Help:      "Number of handled kvstore requests by status code.",
+	}, []string{"method", "code"})
+)
+
+// observe is meant to be deferred with pointer to named error result of handler
+func observe(method string, start time.Time, err *error) {
+	kvstoreRequestDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	kvstoreRequests.WithLabelValues(method, status.Code(*err).String()).Inc()
+}
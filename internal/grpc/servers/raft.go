@@ -6,7 +6,9 @@ import (
 	pb "github.com/HSE-RDBMS-course-work/kvstore-proto/gen/go"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"kvstore/internal/grpc/internal"
 	"kvstore/internal/raft"
 )
 
@@ -34,12 +36,30 @@ func (s *RaftServer) RegisterTo(server *grpc.Server) {
 }
 
 func (s *RaftServer) JoinToCluster(ctx context.Context, in *pb.JoinIn) (*pb.JoinOut, error) {
+	var joinToken, clientAddress string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md[internal.JoinTokenMetaDataKey]; len(values) > 0 {
+			joinToken = values[0]
+		}
+		if values := md[internal.ClientAddressMetaDataKey]; len(values) > 0 {
+			clientAddress = values[0]
+		}
+	}
+
 	err := s.cluster.AcceptJoin(ctx, raft.JoinToClusterIn{
-		JoinerID:      raft.ServerID(in.JoinerId),
-		JoinerAddress: raft.ServerAddress(in.JoinerAddress),
+		JoinerID:            raft.ServerID(in.JoinerId),
+		JoinerAddress:       raft.ServerAddress(in.JoinerAddress),
+		JoinerClientAddress: clientAddress,
+		JoinToken:           joinToken,
 	})
 	if errors.Is(err, raft.ErrIsNotLeader) {
-		return nil, status.Error(codes.FailedPrecondition, err.Error())
+		return nil, internal.NotLeaderError(err)
+	}
+	if errors.Is(err, raft.ErrJoinNotAllowed) {
+		return nil, status.Error(codes.PermissionDenied, err.Error())
+	}
+	if errors.Is(err, raft.ErrNotBootstrapped) {
+		return nil, internal.NotBootstrappedError(err)
 	}
 	if err != nil {
 		return nil, status.Error(codes.Internal, err.Error())
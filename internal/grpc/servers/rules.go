@@ -0,0 +1,102 @@
+package servers
+
+import (
+	pb "github.com/HSE-RDBMS-course-work/kvstore-proto/gen/go"
+	kvpb "kvstore/gen/go"
+	"kvstore/internal/auth"
+	"kvstore/internal/grpc/servers/interceptors"
+	"kvstore/internal/sl"
+	"log/slog"
+)
+
+// methodRules are permissions required to call methods served by node, methods which are not listed are denied
+var methodRules = map[string]interceptors.MethodRule{
+	pb.KVStore_Get_FullMethodName:           {Group: auth.GroupData, Permission: auth.PermissionRead},
+	pb.KVStore_ConsistentGet_FullMethodName: {Group: auth.GroupData, Permission: auth.PermissionRead},
+	pb.KVStore_Put_FullMethodName:           {Group: auth.GroupData, Permission: auth.PermissionWrite},
+	pb.KVStore_Delete_FullMethodName:        {Group: auth.GroupData, Permission: auth.PermissionWrite},
+
+	pb.Raft_JoinToCluster_FullMethodName:              {Group: auth.GroupInternal, Permission: auth.PermissionWrite},
+	kvpb.RaftTransport_AppendEntries_FullMethodName:   {Group: auth.GroupInternal, Permission: auth.PermissionWrite},
+	kvpb.RaftTransport_RequestVote_FullMethodName:     {Group: auth.GroupInternal, Permission: auth.PermissionWrite},
+	kvpb.RaftTransport_RequestPreVote_FullMethodName:  {Group: auth.GroupInternal, Permission: auth.PermissionWrite},
+	kvpb.RaftTransport_TimeoutNow_FullMethodName:      {Group: auth.GroupInternal, Permission: auth.PermissionWrite},
+	kvpb.RaftTransport_InstallSnapshot_FullMethodName: {Group: auth.GroupInternal, Permission: auth.PermissionWrite},
+
+	kvpb.Admin_CreateUser_FullMethodName: {Group: auth.GroupCluster, Permission: auth.PermissionAdmin},
+	kvpb.Admin_DeleteUser_FullMethodName: {Group: auth.GroupCluster, Permission: auth.PermissionAdmin},
+	kvpb.Admin_ListUsers_FullMethodName:  {Group: auth.GroupCluster, Permission: auth.PermissionRead},
+
+	kvpb.Admin_CreateJoinToken_FullMethodName: {Group: auth.GroupCluster, Permission: auth.PermissionAdmin},
+	kvpb.Admin_SetRateLimit_FullMethodName:    {Group: auth.GroupCluster, Permission: auth.PermissionAdmin},
+	kvpb.Admin_DeleteRateLimit_FullMethodName: {Group: auth.GroupCluster, Permission: auth.PermissionAdmin},
+	kvpb.Admin_ListRateLimits_FullMethodName:  {Group: auth.GroupCluster, Permission: auth.PermissionRead},
+	kvpb.Admin_SetLogLevel_FullMethodName:     {Group: auth.GroupCluster, Permission: auth.PermissionAdmin},
+	kvpb.Admin_ListLogLevels_FullMethodName:   {Group: auth.GroupCluster, Permission: auth.PermissionRead},
+	kvpb.Admin_WatchEvents_FullMethodName:     {Group: auth.GroupCluster, Permission: auth.PermissionRead},
+	// users change their own passwords and get their own usage,
+	// permission for other users is checked by handlers
+	kvpb.Admin_ChangePassword_FullMethodName: {},
+	kvpb.Usage_GetUsage_FullMethodName:       {},
+}
+
+// auditedMethods are mutating and administrative methods which are recorded to audit log
+var auditedMethods = map[string]interceptors.AuditKeys{
+	pb.KVStore_Put_FullMethodName: func(in any) []string {
+		return []string{in.(*pb.PutIn).GetKey()}
+	},
+	pb.KVStore_Delete_FullMethodName: func(in any) []string {
+		return []string{in.(*pb.DeleteIn).GetKey()}
+	},
+	pb.Raft_JoinToCluster_FullMethodName: func(in any) []string {
+		req := in.(*pb.JoinIn)
+		return []string{req.GetJoinerId(), req.GetJoinerAddress()}
+	},
+	kvpb.Admin_CreateUser_FullMethodName: func(in any) []string {
+		return []string{in.(*kvpb.CreateUserIn).GetName()}
+	},
+	kvpb.Admin_DeleteUser_FullMethodName: func(in any) []string {
+		return []string{in.(*kvpb.DeleteUserIn).GetName()}
+	},
+	kvpb.Admin_ChangePassword_FullMethodName: func(in any) []string {
+		return []string{in.(*kvpb.ChangePasswordIn).GetName()}
+	},
+	kvpb.Admin_CreateJoinToken_FullMethodName: func(any) []string {
+		return nil
+	},
+	kvpb.Admin_SetRateLimit_FullMethodName: func(in any) []string {
+		return []string{in.(*kvpb.SetRateLimitIn).GetLimit().GetUser()}
+	},
+	kvpb.Admin_DeleteRateLimit_FullMethodName: func(in any) []string {
+		return []string{in.(*kvpb.DeleteRateLimitIn).GetUser()}
+	},
+	kvpb.Admin_SetLogLevel_FullMethodName: func(in any) []string {
+		return []string{in.(*kvpb.SetLogLevelIn).GetComponent()}
+	},
+}
+
+// sampledMethods are methods which payloads are logged by slow log sampling, other methods are never sampled
+// since their payloads may contain credentials, keys and values are redacted by policy of logger
+var sampledMethods = map[string]interceptors.SamplePayload{
+	pb.KVStore_Get_FullMethodName: func(in, out any) ([]slog.Attr, []slog.Attr) {
+		res, _ := out.(*pb.GetOut)
+		return []slog.Attr{sl.Key("key", in.(*pb.GetIn).GetKey())},
+			[]slog.Attr{sl.Value("value", res.GetValue())}
+	},
+	pb.KVStore_ConsistentGet_FullMethodName: func(in, out any) ([]slog.Attr, []slog.Attr) {
+		res, _ := out.(*pb.GetOut)
+		return []slog.Attr{sl.Key("key", in.(*pb.GetIn).GetKey())},
+			[]slog.Attr{sl.Value("value", res.GetValue())}
+	},
+	pb.KVStore_Put_FullMethodName: func(in, _ any) ([]slog.Attr, []slog.Attr) {
+		req := in.(*pb.PutIn)
+		return []slog.Attr{
+			sl.Key("key", req.GetKey()),
+			sl.Value("value", req.GetValue()),
+			slog.Int64("ttl", req.GetTtl()),
+		}, nil
+	},
+	pb.KVStore_Delete_FullMethodName: func(in, _ any) ([]slog.Attr, []slog.Attr) {
+		return []slog.Attr{sl.Key("key", in.(*pb.DeleteIn).GetKey())}, nil
+	},
+}
@@ -0,0 +1,86 @@
+package servers
+
+import (
+	"bytes"
+	pb "github.com/HSE-RDBMS-course-work/kvstore-proto/gen/go"
+	kvpb "kvstore/gen/go"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestSampledMethods(t *testing.T) {
+	tests := []struct {
+		method string
+		in     any
+		out    any
+		want   []string
+	}{
+		{
+			method: pb.KVStore_Get_FullMethodName,
+			in:     &pb.GetIn{Key: "users/1"},
+			out:    &pb.GetOut{Value: "secret value"},
+			want:   []string{"users/1", "sha256:"},
+		},
+		{
+			method: pb.KVStore_ConsistentGet_FullMethodName,
+			in:     &pb.GetIn{Key: "users/1"},
+			// response is nil if request failed
+			out:  nil,
+			want: []string{"users/1"},
+		},
+		{
+			method: pb.KVStore_Put_FullMethodName,
+			in:     &pb.PutIn{Key: "users/1", Value: "secret value", Ttl: 10},
+			out:    &pb.PutOut{},
+			want:   []string{"users/1", "sha256:", "ttl=10"},
+		},
+		{
+			method: pb.KVStore_Delete_FullMethodName,
+			in:     &pb.DeleteIn{Key: "users/1"},
+			out:    &pb.DeleteOut{},
+			want:   []string{"users/1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method, func(t *testing.T) {
+			payload, ok := sampledMethods[tt.method]
+			if !ok {
+				t.Fatalf("%s is not sampled", tt.method)
+			}
+
+			request, response := payload(tt.in, tt.out)
+
+			var out bytes.Buffer
+			slog.New(slog.NewTextHandler(&out, nil)).Info("sampled",
+				slog.Attr{Key: "request", Value: slog.GroupValue(request...)},
+				slog.Attr{Key: "response", Value: slog.GroupValue(response...)},
+			)
+
+			if strings.Contains(out.String(), "secret value") {
+				t.Fatalf("value is not redacted: %s", out.String())
+			}
+			for _, want := range tt.want {
+				if !strings.Contains(out.String(), want) {
+					t.Fatalf("%q is missing in %s", want, out.String())
+				}
+			}
+		})
+	}
+}
+
+func TestCredentialsAreNotSampled(t *testing.T) {
+	for _, method := range []string{
+		kvpb.Auth_Authenticate_FullMethodName,
+		kvpb.Admin_CreateUser_FullMethodName,
+		kvpb.Admin_ChangePassword_FullMethodName,
+		kvpb.Admin_CreateJoinToken_FullMethodName,
+		pb.Raft_JoinToCluster_FullMethodName,
+		kvpb.RaftTransport_AppendEntries_FullMethodName,
+	} {
+		if _, ok := sampledMethods[method]; ok {
+			t.Fatalf("%s is sampled", method)
+		}
+	}
+}
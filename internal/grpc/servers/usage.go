@@ -0,0 +1,83 @@
+package servers
+
+import (
+	"context"
+	"errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	kvpb "kvstore/gen/go"
+	"kvstore/internal/auth"
+	"kvstore/internal/core"
+)
+
+type usageReporter interface {
+	Usage(ctx context.Context, user string) (core.UsageReport, error)
+}
+
+type UsageServer struct {
+	kvpb.UnimplementedUsageServer
+	usage  usageReporter
+	policy policy
+}
+
+func NewUsageServer(usage usageReporter, policy policy) (*UsageServer, error) {
+	if usage == nil {
+		return nil, errors.New("usage is required")
+	}
+	if policy == nil {
+		return nil, errors.New("policy is required")
+	}
+
+	return &UsageServer{
+		usage:  usage,
+		policy: policy,
+	}, nil
+}
+
+func (s *UsageServer) RegisterTo(server *grpc.Server) {
+	kvpb.RegisterUsageServer(server, s)
+}
+
+func (s *UsageServer) GetUsage(ctx context.Context, in *kvpb.GetUsageIn) (*kvpb.GetUsageOut, error) {
+	id, ok := auth.IdentityFrom(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "caller is not authenticated")
+	}
+
+	user := in.GetUser()
+	if user == "" {
+		user = id.User
+	}
+	if user != id.User {
+		err := s.policy.Authorize(id, auth.Request{Group: auth.GroupCluster, Permission: auth.PermissionRead})
+		if err != nil {
+			return nil, status.Error(codes.PermissionDenied, "only admin can get usage of other users")
+		}
+	}
+
+	report, err := s.usage.Usage(ctx, user)
+	if err != nil {
+		return nil, status.Error(codes.Internal, "failed to get usage")
+	}
+
+	out := kvpb.GetUsageOut{
+		User:     usageEntry(report.User),
+		Prefixes: make([]*kvpb.UsageEntry, 0, len(report.Prefixes)),
+	}
+	for _, prefix := range report.Prefixes {
+		out.Prefixes = append(out.Prefixes, usageEntry(prefix))
+	}
+
+	return &out, nil
+}
+
+func usageEntry(entry core.UsageEntry) *kvpb.UsageEntry {
+	return &kvpb.UsageEntry{
+		Name:     entry.Name,
+		Keys:     entry.Usage.Keys,
+		Bytes:    entry.Usage.Bytes,
+		MaxKeys:  entry.Quota.MaxKeys,
+		MaxBytes: entry.Quota.MaxBytes,
+	}
+}
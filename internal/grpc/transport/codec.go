@@ -0,0 +1,22 @@
+package transport
+
+import (
+	"bytes"
+	"fmt"
+	"github.com/hashicorp/go-msgpack/v2/codec"
+)
+
+func encode(in any) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := codec.NewEncoder(&buf, &codec.MsgpackHandle{}).Encode(in); err != nil {
+		return nil, fmt.Errorf("cannot encode raft message: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func decode(payload []byte, out any) error {
+	if err := codec.NewDecoderBytes(payload, &codec.MsgpackHandle{}).Decode(out); err != nil {
+		return fmt.Errorf("cannot decode raft message: %w", err)
+	}
+	return nil
+}
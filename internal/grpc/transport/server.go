@@ -0,0 +1,146 @@
+package transport
+
+import (
+	"context"
+	"github.com/hashicorp/raft"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	kvpb "kvstore/gen/go"
+)
+
+// transportServer receives raft RPCs from other nodes and passes them to raft through transport consumer
+type transportServer struct {
+	kvpb.UnimplementedRaftTransportServer
+	transport *Transport
+}
+
+func (s *transportServer) AppendEntries(ctx context.Context, in *kvpb.RaftMessage) (*kvpb.RaftMessage, error) {
+	var req raft.AppendEntriesRequest
+	if err := decode(in.GetPayload(), &req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return s.transport.handle(ctx, &req, nil, isHeartbeat(&req))
+}
+
+func (s *transportServer) RequestVote(ctx context.Context, in *kvpb.RaftMessage) (*kvpb.RaftMessage, error) {
+	var req raft.RequestVoteRequest
+	if err := decode(in.GetPayload(), &req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return s.transport.handle(ctx, &req, nil, false)
+}
+
+func (s *transportServer) RequestPreVote(ctx context.Context, in *kvpb.RaftMessage) (*kvpb.RaftMessage, error) {
+	var req raft.RequestPreVoteRequest
+	if err := decode(in.GetPayload(), &req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return s.transport.handle(ctx, &req, nil, false)
+}
+
+func (s *transportServer) TimeoutNow(ctx context.Context, in *kvpb.RaftMessage) (*kvpb.RaftMessage, error) {
+	var req raft.TimeoutNowRequest
+	if err := decode(in.GetPayload(), &req); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return s.transport.handle(ctx, &req, nil, false)
+}
+
+func (s *transportServer) InstallSnapshot(stream kvpb.RaftTransport_InstallSnapshotServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+
+	var req raft.InstallSnapshotRequest
+	if err := decode(first.GetRequest(), &req); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	data := &chunkReader{
+		stream: stream,
+		buf:    first.GetData(),
+	}
+
+	out, err := s.transport.handle(stream.Context(), &req, data, false)
+	if err != nil {
+		return err
+	}
+
+	return stream.SendAndClose(out)
+}
+
+// handle passes rpc to raft and waits for its response
+func (t *Transport) handle(ctx context.Context, command any, data io.Reader, heartbeat bool) (*kvpb.RaftMessage, error) {
+	respCh := make(chan raft.RPCResponse, 1)
+	rpc := raft.RPC{
+		Command:  command,
+		Reader:   data,
+		RespChan: respCh,
+	}
+
+	if heartbeat && t.dispatchHeartbeat(rpc) {
+		return t.response(ctx, respCh)
+	}
+
+	select {
+	case t.consumer <- rpc:
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	case <-t.shutdownCh:
+		return nil, status.Error(codes.Unavailable, ErrShutdown.Error())
+	}
+
+	return t.response(ctx, respCh)
+}
+
+func (t *Transport) dispatchHeartbeat(rpc raft.RPC) bool {
+	t.heartbeatMu.Lock()
+	fn := t.heartbeatFn
+	t.heartbeatMu.Unlock()
+
+	if fn == nil {
+		return false
+	}
+
+	fn(rpc)
+
+	return true
+}
+
+func (t *Transport) response(ctx context.Context, respCh <-chan raft.RPCResponse) (*kvpb.RaftMessage, error) {
+	select {
+	case resp := <-respCh:
+		if resp.Error != nil {
+			return nil, status.Error(codes.Unknown, resp.Error.Error())
+		}
+
+		payload, err := encode(resp.Response)
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		return &kvpb.RaftMessage{Payload: payload}, nil
+	case <-ctx.Done():
+		return nil, status.FromContextError(ctx.Err()).Err()
+	case <-t.shutdownCh:
+		return nil, status.Error(codes.Unavailable, ErrShutdown.Error())
+	}
+}
+
+// isHeartbeat reports whether request is a heartbeat which raft allows to handle out of its main loop
+func isHeartbeat(req *raft.AppendEntriesRequest) bool {
+	leader := req.RPCHeader.Addr
+	if len(leader) == 0 {
+		leader = req.Leader // older raft versions fill only this field
+	}
+
+	return req.Term != 0 && leader != nil &&
+		req.PrevLogEntry == 0 && req.PrevLogTerm == 0 &&
+		len(req.Entries) == 0 && req.LeaderCommitIndex == 0
+}
@@ -0,0 +1,80 @@
+package transport
+
+import (
+	"context"
+	"errors"
+	"github.com/hashicorp/raft"
+	"io"
+	kvpb "kvstore/gen/go"
+	"time"
+)
+
+func (t *Transport) InstallSnapshot(_ raft.ServerID, target raft.ServerAddress, args *raft.InstallSnapshotRequest, resp *raft.InstallSnapshotResponse, data io.Reader) error {
+	client, err := t.client(target)
+	if err != nil {
+		return err
+	}
+
+	request, err := encode(args)
+	if err != nil {
+		return err
+	}
+
+	timeout := t.timeout * time.Duration(max(1, args.Size/timeoutScale))
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	stream, err := client.InstallSnapshot(ctx)
+	if err != nil {
+		return err
+	}
+
+	if err := stream.Send(&kvpb.SnapshotChunk{Request: request}); err != nil {
+		return err
+	}
+
+	buf := make([]byte, chunkSize)
+	for {
+		n, err := data.Read(buf)
+		if n > 0 {
+			if err := stream.Send(&kvpb.SnapshotChunk{Data: buf[:n]}); err != nil {
+				return err
+			}
+		}
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	out, err := stream.CloseAndRecv()
+	if err != nil {
+		return err
+	}
+
+	return decode(out.GetPayload(), resp)
+}
+
+// chunkReader reads snapshot data from chunks of the install snapshot stream
+type chunkReader struct {
+	stream kvpb.RaftTransport_InstallSnapshotServer
+	buf    []byte
+}
+
+func (r *chunkReader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		chunk, err := r.stream.Recv()
+		if err != nil {
+			return 0, err
+		}
+		r.buf = chunk.GetData()
+	}
+
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+
+	return n, nil
+}
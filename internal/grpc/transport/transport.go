@@ -0,0 +1,216 @@
+package transport
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/raft"
+	"google.golang.org/grpc"
+	kvpb "kvstore/gen/go"
+	"kvstore/internal/grpc/clients/interceptors"
+	"kvstore/internal/grpc/internal"
+	"kvstore/internal/sl"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	defaultTimeout = 10 * time.Second
+	// timeoutScale is amount of snapshot bytes which is given one more timeout to be sent
+	timeoutScale = 256 * 1024
+	chunkSize    = 64 * 1024
+)
+
+var ErrShutdown = errors.New("transport is shut down")
+
+type Config struct {
+	// Address is advertised public address of this node which is used as its raft address
+	Address  string
+	Timeout  time.Duration
+	Username string
+	Password sl.Secret
+	// ClusterSecret is used instead of username and password if it is provided
+	ClusterSecret sl.Secret
+}
+
+// Transport is raft.Transport multiplexed over grpc server of the node,
+// so raft RPCs go through the same listener, credentials and interceptors as client requests
+type Transport struct {
+	logger     *slog.Logger
+	address    raft.ServerAddress
+	timeout    time.Duration
+	opts       []grpc.DialOption
+	consumer   chan raft.RPC
+	shutdownCh chan struct{}
+	shutdown   *sync.Once
+
+	mu    *sync.Mutex
+	conns map[raft.ServerAddress]*grpc.ClientConn
+
+	heartbeatMu *sync.Mutex
+	heartbeatFn func(raft.RPC)
+}
+
+// New creates transport which uses tls for outgoing connections if tls config is provided
+func New(logger *slog.Logger, tlsConfig *tls.Config, conf Config) (*Transport, error) {
+	if logger == nil {
+		return nil, errors.New("logger required")
+	}
+
+	logger = logger.With(sl.Component("grpc.Transport"))
+
+	if conf.Address == "" {
+		return nil, errors.New("address required")
+	}
+	if conf.ClusterSecret == "" && conf.Username == "" {
+		return nil, errors.New("username required")
+	}
+	if conf.ClusterSecret == "" && conf.Password == "" {
+		return nil, errors.New("password required")
+	}
+	if conf.Timeout <= 0 {
+		conf.Timeout = defaultTimeout
+	}
+
+	unaryAuth := interceptors.NewClusterAuth(string(conf.ClusterSecret))
+	streamAuth := interceptors.NewStreamClusterAuth(string(conf.ClusterSecret))
+	if conf.ClusterSecret == "" {
+		unaryAuth = interceptors.NewAuth(conf.Username, string(conf.Password))
+		streamAuth = interceptors.NewStreamAuth(conf.Username, string(conf.Password))
+	}
+
+	opts := []grpc.DialOption{
+		grpc.WithTransportCredentials(internal.TransportCredentials(tlsConfig)),
+		grpc.WithUnaryInterceptor(unaryAuth),
+		grpc.WithStreamInterceptor(streamAuth),
+	}
+
+	logger.Debug("created successfully", sl.Conf(conf))
+
+	return &Transport{
+		logger:      logger,
+		address:     raft.ServerAddress(conf.Address),
+		timeout:     conf.Timeout,
+		opts:        opts,
+		consumer:    make(chan raft.RPC),
+		shutdownCh:  make(chan struct{}),
+		shutdown:    new(sync.Once),
+		mu:          new(sync.Mutex),
+		conns:       make(map[raft.ServerAddress]*grpc.ClientConn),
+		heartbeatMu: new(sync.Mutex),
+	}, nil
+}
+
+func (t *Transport) RegisterTo(server *grpc.Server) {
+	kvpb.RegisterRaftTransportServer(server, &transportServer{transport: t})
+}
+
+func (t *Transport) Consumer() <-chan raft.RPC {
+	return t.consumer
+}
+
+func (t *Transport) LocalAddr() raft.ServerAddress {
+	return t.address
+}
+
+func (t *Transport) AppendEntriesPipeline(_ raft.ServerID, _ raft.ServerAddress) (raft.AppendPipeline, error) {
+	return nil, raft.ErrPipelineReplicationNotSupported
+}
+
+func (t *Transport) AppendEntries(_ raft.ServerID, target raft.ServerAddress, args *raft.AppendEntriesRequest, resp *raft.AppendEntriesResponse) error {
+	return t.call(target, kvpb.RaftTransportClient.AppendEntries, args, resp)
+}
+
+func (t *Transport) RequestVote(_ raft.ServerID, target raft.ServerAddress, args *raft.RequestVoteRequest, resp *raft.RequestVoteResponse) error {
+	return t.call(target, kvpb.RaftTransportClient.RequestVote, args, resp)
+}
+
+func (t *Transport) RequestPreVote(_ raft.ServerID, target raft.ServerAddress, args *raft.RequestPreVoteRequest, resp *raft.RequestPreVoteResponse) error {
+	return t.call(target, kvpb.RaftTransportClient.RequestPreVote, args, resp)
+}
+
+func (t *Transport) TimeoutNow(_ raft.ServerID, target raft.ServerAddress, args *raft.TimeoutNowRequest, resp *raft.TimeoutNowResponse) error {
+	return t.call(target, kvpb.RaftTransportClient.TimeoutNow, args, resp)
+}
+
+func (t *Transport) EncodePeer(_ raft.ServerID, address raft.ServerAddress) []byte {
+	return []byte(address)
+}
+
+func (t *Transport) DecodePeer(buf []byte) raft.ServerAddress {
+	return raft.ServerAddress(buf)
+}
+
+func (t *Transport) SetHeartbeatHandler(cb func(rpc raft.RPC)) {
+	t.heartbeatMu.Lock()
+	defer t.heartbeatMu.Unlock()
+
+	t.heartbeatFn = cb
+}
+
+func (t *Transport) Close() error {
+	t.shutdown.Do(func() {
+		close(t.shutdownCh)
+	})
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	var errs []error
+	for address, conn := range t.conns {
+		if err := conn.Close(); err != nil {
+			errs = append(errs, fmt.Errorf("cannot close connection to %s: %w", address, err))
+		}
+		delete(t.conns, address)
+	}
+
+	return errors.Join(errs...)
+}
+
+type unaryCall = func(kvpb.RaftTransportClient, context.Context, *kvpb.RaftMessage, ...grpc.CallOption) (*kvpb.RaftMessage, error)
+
+func (t *Transport) call(target raft.ServerAddress, method unaryCall, args, resp any) error {
+	client, err := t.client(target)
+	if err != nil {
+		return err
+	}
+
+	payload, err := encode(args)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), t.timeout)
+	defer cancel()
+
+	out, err := method(client, ctx, &kvpb.RaftMessage{Payload: payload})
+	if err != nil {
+		return err
+	}
+
+	return decode(out.GetPayload(), resp)
+}
+
+func (t *Transport) client(target raft.ServerAddress) (kvpb.RaftTransportClient, error) {
+	select {
+	case <-t.shutdownCh:
+		return nil, ErrShutdown
+	default:
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	conn, ok := t.conns[target]
+	if !ok {
+		var err error
+		if conn, err = grpc.NewClient(string(target), t.opts...); err != nil {
+			return nil, fmt.Errorf("cannot create connection to %s: %w", target, err)
+		}
+		t.conns[target] = conn
+	}
+
+	return kvpb.NewRaftTransportClient(conn), nil
+}
@@ -3,29 +3,76 @@ package raft
 import (
 	"context"
 	"github.com/hashicorp/raft"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"kvstore/internal/auth"
 	"kvstore/internal/core"
+	"kvstore/internal/ratelimit"
+	"kvstore/internal/sl"
 	"log/slog"
 	"time"
 )
 
+var tracer = otel.Tracer("kvstore/internal/raft")
+
 type ServerID = raft.ServerID
 
 type ServerAddress = raft.ServerAddress
 
+type Transport = raft.Transport
+
 type kvstore interface {
 	Get(context.Context, core.Key) (*core.Value, error)
-	Put(context.Context, core.Key, core.Value, time.Duration) error
+	CheckQuota(context.Context, core.Key, core.Value, string) error
+	Put(context.Context, core.Key, core.Value, time.Duration, string) error
 	Delete(context.Context, core.Key) error
 	Expired(context.Context) <-chan core.Key
 	Snapshot(context.Context) (core.Snapshot, error)
 	Load(context.Context, core.Snapshot) error
 }
 
+type users interface {
+	Create(context.Context, auth.User) error
+	SetPasswordHash(context.Context, string, []byte) error
+	Delete(context.Context, string) error
+	Snapshot(context.Context) (map[string]auth.User, error)
+	Load(context.Context, map[string]auth.User) error
+}
+
+type joinTokens interface {
+	Create(context.Context, auth.JoinToken, time.Time) error
+	Use(context.Context, string, time.Time) error
+	Snapshot(context.Context) (map[string]auth.JoinToken, error)
+	Load(context.Context, map[string]auth.JoinToken) error
+}
+
+type rateLimits interface {
+	Set(context.Context, ratelimit.UserLimit) error
+	Delete(context.Context, string) error
+	Snapshot(context.Context) (map[string]ratelimit.Limit, error)
+	Load(context.Context, map[string]ratelimit.Limit) error
+}
+
+type slowLog interface {
+	Begin(ctx context.Context, operation string) (context.Context, func(err error))
+}
+
 type operation string
 
 const (
-	opPut    operation = "put"
-	opDelete operation = "delete"
+	opPut             operation = "put"
+	opDelete          operation = "delete"
+	opCreateUser      operation = "create_user"
+	opSetPasswordHash operation = "set_password_hash"
+	opDeleteUser      operation = "delete_user"
+
+	opCreateJoinToken operation = "create_join_token"
+	opUseJoinToken    operation = "use_join_token"
+
+	opSetRateLimit    operation = "set_rate_limit"
+	opDeleteRateLimit operation = "delete_rate_limit"
+
+	opSetNodeAddress operation = "set_node_address"
 )
 
 type command struct {
@@ -33,14 +80,65 @@ type command struct {
 	Key   core.Key      `json:"key"`
 	Value core.Value    `json:"value"`
 	TTL   time.Duration `json:"ttl"`
+	// Owner is user who writes key, usage of the key is accounted to this user
+	Owner string     `json:"owner,omitempty"`
+	User  *auth.User `json:"user,omitempty"`
+
+	JoinToken *auth.JoinToken      `json:"join_token,omitempty"`
+	RateLimit *ratelimit.UserLimit `json:"rate_limit,omitempty"`
+	Node      *nodeAddress         `json:"node,omitempty"`
+
+	// Trace carries trace context of proposer, so spans of applying on every node belong to its trace
+	Trace propagation.MapCarrier `json:"trace,omitempty"`
+}
+
+// nodeAddress is client address of node which is registered in directory
+type nodeAddress struct {
+	ID      ServerID `json:"id"`
+	Address string   `json:"address"`
 }
 
 func (cmd *command) LogAttr() slog.Attr {
+	if cmd.Node != nil {
+		return slog.Group(
+			"command",
+			slog.String("op", string(cmd.Op)),
+			slog.String("node", string(cmd.Node.ID)),
+			slog.String("address", cmd.Node.Address),
+		)
+	}
+
+	if cmd.RateLimit != nil {
+		return slog.Group(
+			"command",
+			slog.String("op", string(cmd.Op)),
+			slog.String("user", cmd.RateLimit.User),
+			slog.Float64("rate", cmd.RateLimit.Limit.Rate),
+			slog.Int("burst", cmd.RateLimit.Limit.Burst),
+		)
+	}
+
+	if cmd.JoinToken != nil {
+		return slog.Group(
+			"command",
+			slog.String("op", string(cmd.Op)),
+			slog.Time("expires_at", cmd.JoinToken.ExpiresAt),
+		)
+	}
+
+	if cmd.User != nil {
+		return slog.Group(
+			"command",
+			slog.String("op", string(cmd.Op)),
+			slog.String("user", cmd.User.Name),
+		)
+	}
+
 	return slog.Group(
 		"command",
 		slog.String("op", string(cmd.Op)),
-		slog.String("key", string(cmd.Key)),
-		slog.String("value", string(cmd.Value)),
+		sl.Key("key", string(cmd.Key)),
+		sl.Value("value", string(cmd.Value)),
 		slog.Duration("ttl", cmd.TTL),
 	)
 }
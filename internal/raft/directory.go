@@ -0,0 +1,50 @@
+package raft
+
+import (
+	"maps"
+	"sync"
+)
+
+// Directory is replicated map of node ids to their client addresses,
+// raft knows only raft addresses, so it is used to point clients to the leader
+type Directory struct {
+	mu        *sync.RWMutex
+	addresses map[ServerID]string
+}
+
+func NewDirectory() *Directory {
+	return &Directory{
+		mu:        new(sync.RWMutex),
+		addresses: make(map[ServerID]string),
+	}
+}
+
+func (d *Directory) Address(id ServerID) (string, bool) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	address, ok := d.addresses[id]
+	return address, ok
+}
+
+func (d *Directory) set(id ServerID, address string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.addresses[id] = address
+}
+
+func (d *Directory) snapshot() map[ServerID]string {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	return maps.Clone(d.addresses)
+}
+
+func (d *Directory) load(addresses map[ServerID]string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.addresses = make(map[ServerID]string, len(addresses))
+	maps.Copy(d.addresses, addresses)
+}
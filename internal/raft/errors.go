@@ -4,24 +4,34 @@ import (
 	"errors"
 	"fmt"
 	"github.com/hashicorp/raft"
+	"maps"
 )
 
 var (
 	ErrIsNotLeader = errors.New("this node is not a leader")
 	ErrUnknownCmd  = errors.New("unknown command")
+
+	ErrNotBootstrapped = errors.New("cluster is not bootstrapped yet")
+	ErrJoinNotAllowed  = errors.New("node is not allowed to join")
 )
 
 type ErrorIsNotLeader struct {
 	err           error
 	leaderAddress string
+	leaderID      string
+	// leaderClient is client address of the leader, it is empty until the leader registers it in directory
+	leaderClient string
 }
 
-func newErrorIsNotLeader(r *raft.Raft) *ErrorIsNotLeader {
-	_, leaderPublicAddress := r.LeaderWithID()
+func newErrorIsNotLeader(r *raft.Raft, directory *Directory) *ErrorIsNotLeader {
+	leaderAddress, leaderID := r.LeaderWithID()
+	leaderClient, _ := directory.Address(leaderID)
 
 	return &ErrorIsNotLeader{
 		err:           ErrIsNotLeader,
-		leaderAddress: string(leaderPublicAddress),
+		leaderAddress: string(leaderAddress),
+		leaderID:      string(leaderID),
+		leaderClient:  leaderClient,
 	}
 }
 
@@ -39,3 +49,33 @@ func (e *ErrorIsNotLeader) Leader() string {
 	}
 	return e.leaderAddress
 }
+
+// LeaderHint returns client address and id of the current leader if this node knows them
+func (e *ErrorIsNotLeader) LeaderHint() (string, ServerID, bool) {
+	if e.leaderClient == "" {
+		return "", "", false
+	}
+	return e.leaderClient, ServerID(e.leaderID), true
+}
+
+// ErrorNotBootstrapped carries peers known by the node which waits for bootstrap,
+// joiners merge them, so every node learns the same peers and the same bootstrapper is chosen
+type ErrorNotBootstrapped struct {
+	peers map[ServerID]ServerAddress
+}
+
+func NewErrorNotBootstrapped(peers map[ServerID]ServerAddress) *ErrorNotBootstrapped {
+	return &ErrorNotBootstrapped{peers: maps.Clone(peers)}
+}
+
+func (e *ErrorNotBootstrapped) Error() string {
+	return fmt.Sprintf("%s, %d peers are known", ErrNotBootstrapped, len(e.peers))
+}
+
+func (e *ErrorNotBootstrapped) Unwrap() error {
+	return ErrNotBootstrapped
+}
+
+func (e *ErrorNotBootstrapped) Peers() map[ServerID]ServerAddress {
+	return maps.Clone(e.peers)
+}
@@ -0,0 +1,201 @@
+package raft
+
+import (
+	"context"
+	"errors"
+	"github.com/hashicorp/raft"
+	"kvstore/internal/sl"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	EventLeaderChanged    = "leader_changed"
+	EventStateChanged     = "state_changed"
+	EventPeerAdded        = "peer_added"
+	EventPeerRemoved      = "peer_removed"
+	EventHeartbeatFailed  = "heartbeat_failed"
+	EventHeartbeatResumed = "heartbeat_resumed"
+	EventSnapshotCreated  = "snapshot_created"
+	EventSnapshotRestored = "snapshot_restored"
+
+	observationsBuffer = 64
+	subscriberBuffer   = 64
+)
+
+// Event is change of cluster seen by this node, peer and heartbeat events are emitted only by the leader
+type Event struct {
+	Time   time.Time
+	Type   string
+	NodeID ServerID
+	Term   uint64
+	// State is new raft state of this node for state_changed
+	State string
+	// ServerID and ServerAddress are of the new leader for leader_changed and of the peer for peer and heartbeat events
+	ServerID      ServerID
+	ServerAddress ServerAddress
+	// LastContact is time of the last successful heartbeat for heartbeat_failed
+	LastContact time.Time
+}
+
+// Events fans out raft observations and snapshot events of FSM to subscribers
+type Events struct {
+	logger       *slog.Logger
+	nodeID       ServerID
+	mu           *sync.Mutex
+	raft         *raft.Raft
+	observer     *raft.Observer
+	observations chan raft.Observation
+	stopped      bool
+	subscribers  map[chan Event]struct{}
+}
+
+func NewEvents(logger *slog.Logger, nodeID ServerID) (*Events, error) {
+	if logger == nil {
+		return nil, errors.New("logger required")
+	}
+
+	logger = logger.With(sl.Component("raft.Events"))
+
+	if nodeID == "" {
+		return nil, errors.New("node id required")
+	}
+
+	logger.Debug("created successfully")
+
+	return &Events{
+		logger:       logger,
+		nodeID:       nodeID,
+		mu:           new(sync.Mutex),
+		observations: make(chan raft.Observation, observationsBuffer),
+		subscribers:  make(map[chan Event]struct{}),
+	}, nil
+}
+
+// Subscribe returns channel of events which is closed when context is done, events are stopped
+// or subscriber does not keep up with events, so subscriber never misses events silently
+func (e *Events) Subscribe(ctx context.Context) <-chan Event {
+	events := make(chan Event, subscriberBuffer)
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.stopped {
+		close(events)
+		return events
+	}
+
+	e.subscribers[events] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+
+		e.mu.Lock()
+		defer e.mu.Unlock()
+
+		e.unsubscribe(events)
+	}()
+
+	return events
+}
+
+// observe registers observer right after raft is created, so observations made before Run starts
+// are buffered and the first leader election is not missed
+func (e *Events) observe(r *raft.Raft) {
+	observer := raft.NewObserver(e.observations, false, func(o *raft.Observation) bool {
+		switch o.Data.(type) {
+		case raft.LeaderObservation, raft.RaftState, raft.PeerObservation,
+			raft.FailedHeartbeatObservation, raft.ResumedHeartbeatObservation:
+			return true
+		default:
+			return false
+		}
+	})
+
+	e.mu.Lock()
+	e.raft = r
+	e.observer = observer
+	e.mu.Unlock()
+
+	r.RegisterObserver(observer)
+}
+
+// Run publishes observations of raft until context is done, then closes channels of subscribers
+func (e *Events) Run(ctx context.Context) error {
+	defer e.stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case o := <-e.observations:
+			e.publish(observationEvent(o.Data))
+		}
+	}
+}
+
+func observationEvent(data any) Event {
+	switch o := data.(type) {
+	case raft.LeaderObservation:
+		return Event{Type: EventLeaderChanged, ServerID: o.LeaderID, ServerAddress: o.LeaderAddr}
+	case raft.RaftState:
+		return Event{Type: EventStateChanged, State: o.String()}
+	case raft.PeerObservation:
+		event := Event{Type: EventPeerAdded, ServerID: o.Peer.ID, ServerAddress: o.Peer.Address}
+		if o.Removed {
+			event.Type = EventPeerRemoved
+		}
+		return event
+	case raft.FailedHeartbeatObservation:
+		return Event{Type: EventHeartbeatFailed, ServerID: o.PeerID, LastContact: o.LastContact}
+	default:
+		return Event{Type: EventHeartbeatResumed, ServerID: data.(raft.ResumedHeartbeatObservation).PeerID}
+	}
+}
+
+// publish never blocks, subscriber whose buffer is full is closed
+func (e *Events) publish(event Event) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	event.Time = time.Now()
+	event.NodeID = e.nodeID
+	if e.raft != nil {
+		event.Term = e.raft.CurrentTerm()
+	}
+
+	eventsTotal.WithLabelValues(event.Type).Inc()
+
+	for events := range e.subscribers {
+		select {
+		case events <- event:
+		default:
+			e.logger.Warn("subscriber does not keep up with events, closing it")
+			e.unsubscribe(events)
+		}
+	}
+}
+
+func (e *Events) stop() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.observer != nil {
+		e.raft.DeregisterObserver(e.observer)
+	}
+
+	e.stopped = true
+	for events := range e.subscribers {
+		e.unsubscribe(events)
+	}
+}
+
+func (e *Events) unsubscribe(events chan Event) {
+	if _, ok := e.subscribers[events]; !ok {
+		return
+	}
+
+	delete(e.subscribers, events)
+	close(events)
+}
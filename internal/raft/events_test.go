@@ -0,0 +1,72 @@
+package raft
+
+import (
+	"context"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/raft"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+func TestFirstLeaderEventIsDelivered(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	logger := slog.New(slog.DiscardHandler)
+
+	events, err := NewEvents(logger, "node-1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	subscription := events.Subscribe(ctx)
+
+	address, transport := raft.NewInmemTransport("")
+
+	r, _, err := New(logger, hclog.NewNullLogger(), &raft.MockFSM{}, transport, events, Config{
+		AdvertisedAddress: string(address),
+		NodeID:            "node-1",
+		DataLocation:      t.TempDir(),
+		SnapshotsRetain:   1,
+		Tuning: Tuning{
+			HeartbeatTimeout:   50 * time.Millisecond,
+			ElectionTimeout:    50 * time.Millisecond,
+			LeaderLeaseTimeout: 50 * time.Millisecond,
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Shutdown()
+
+	err = r.BootstrapCluster(raft.Configuration{
+		Servers: []raft.Server{{ID: "node-1", Address: address}},
+	}).Error()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// events start to be published only after the node is elected like when Run is started late
+	select {
+	case <-r.LeaderCh():
+	case <-time.After(5 * time.Second):
+		t.Fatal("node is not elected")
+	}
+
+	go events.Run(ctx)
+
+	timeout := time.After(5 * time.Second)
+	for {
+		select {
+		case event, ok := <-subscription:
+			if !ok {
+				t.Fatal("subscription is closed")
+			}
+			if event.Type == EventLeaderChanged && event.ServerID == "node-1" {
+				return
+			}
+		case <-timeout:
+			t.Fatal("leader_changed event is not delivered")
+		}
+	}
+}
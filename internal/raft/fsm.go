@@ -5,19 +5,31 @@ import (
 	"encoding/json"
 	"errors"
 	"github.com/hashicorp/raft"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
 	"io"
+	"kvstore/internal/core"
 	"kvstore/internal/sl"
 	"log/slog"
+	"time"
 )
 
 // FSM is an implementation of final state machine
 // it is used by raft to apply logs from leader or from snapshots to store
 type FSM struct {
-	logger *slog.Logger
-	store  kvstore
+	logger    *slog.Logger
+	store     kvstore
+	users     users
+	tokens    joinTokens
+	limits    rateLimits
+	sizes     core.SizeLimits
+	events    *Events
+	directory *Directory
 }
 
-func NewFSM(logger *slog.Logger, store kvstore) (*FSM, error) {
+func NewFSM(logger *slog.Logger, store kvstore, users users, tokens joinTokens, limits rateLimits, directory *Directory, events *Events, sizes core.SizeLimits) (*FSM, error) {
 	if logger == nil {
 		return nil, errors.New("logger required")
 	}
@@ -27,38 +39,141 @@ func NewFSM(logger *slog.Logger, store kvstore) (*FSM, error) {
 	if store == nil {
 		return nil, errors.New("store required")
 	}
+	if users == nil {
+		return nil, errors.New("users required")
+	}
+	if tokens == nil {
+		return nil, errors.New("join tokens required")
+	}
+	if limits == nil {
+		return nil, errors.New("rate limits required")
+	}
+	if directory == nil {
+		return nil, errors.New("directory required")
+	}
+	if events == nil {
+		return nil, errors.New("events required")
+	}
 
-	logger.Debug("created successfully")
+	logger.Debug("created successfully", sl.Conf(sizes))
 
 	return &FSM{
-		logger: logger,
-		store:  store,
+		logger:    logger,
+		store:     store,
+		users:     users,
+		tokens:    tokens,
+		limits:    limits,
+		sizes:     sizes,
+		events:    events,
+		directory: directory,
 	}, nil
 }
 
+// applied is response of FSM.Apply which is returned to proposer by raft future
+type applied struct {
+	err      error
+	duration time.Duration
+}
+
 func (fsm *FSM) Apply(log *raft.Log) any {
+	start := time.Now()
+
 	var cmd command
 	if err := json.Unmarshal(log.Data, &cmd); err != nil {
 		fsm.logger.Warn("got incorrect json with command", sl.Error(err))
-		return err
+		return applied{err: err, duration: time.Since(start)}
 	}
 
 	fsm.logger.Debug("applying command", cmd.LogAttr())
 
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), cmd.Trace)
+	ctx, span := tracer.Start(ctx, "raft.FSM.Apply", trace.WithAttributes(
+		attribute.String("op", string(cmd.Op)),
+		attribute.Int64("index", int64(log.Index)),
+	))
+	defer span.End()
+
 	var err error
 	switch cmd.Op {
 	case opPut:
-		err = fsm.store.Put(context.Background(), cmd.Key, cmd.Value, cmd.TTL)
+		err = fsm.applyPut(ctx, cmd)
 	case opDelete:
-		err = fsm.store.Delete(context.Background(), cmd.Key)
+		err = fsm.store.Delete(ctx, cmd.Key)
+	case opCreateUser, opSetPasswordHash, opDeleteUser:
+		err = fsm.applyUser(ctx, cmd)
+	case opCreateJoinToken, opUseJoinToken:
+		err = fsm.applyJoinToken(ctx, cmd, log.AppendedAt)
+	case opSetRateLimit, opDeleteRateLimit:
+		err = fsm.applyRateLimit(ctx, cmd)
+	case opSetNodeAddress:
+		err = fsm.applyNodeAddress(cmd)
 	default:
 		err = ErrUnknownCmd
 	}
 
 	if err != nil {
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return applied{err: err, duration: time.Since(start)}
+}
+
+// applyPut checks sizes again, so entry proposed bypassing store does not get into state
+func (fsm *FSM) applyPut(ctx context.Context, cmd command) error {
+	if err := fsm.sizes.CheckEntry(cmd.Key, cmd.Value); err != nil {
 		return err
 	}
 
+	return fsm.store.Put(ctx, cmd.Key, cmd.Value, cmd.TTL, cmd.Owner)
+}
+
+func (fsm *FSM) applyUser(ctx context.Context, cmd command) error {
+	if cmd.User == nil {
+		return errors.New("user required")
+	}
+
+	switch cmd.Op {
+	case opCreateUser:
+		return fsm.users.Create(ctx, *cmd.User)
+	case opSetPasswordHash:
+		return fsm.users.SetPasswordHash(ctx, cmd.User.Name, cmd.User.PasswordHash)
+	default:
+		return fsm.users.Delete(ctx, cmd.User.Name)
+	}
+}
+
+// applyJoinToken uses time when leader appended log, so every node gets the same result
+func (fsm *FSM) applyJoinToken(ctx context.Context, cmd command, now time.Time) error {
+	if cmd.JoinToken == nil {
+		return errors.New("join token required")
+	}
+
+	if cmd.Op == opCreateJoinToken {
+		return fsm.tokens.Create(ctx, *cmd.JoinToken, now)
+	}
+
+	return fsm.tokens.Use(ctx, cmd.JoinToken.Hash, now)
+}
+
+func (fsm *FSM) applyRateLimit(ctx context.Context, cmd command) error {
+	if cmd.RateLimit == nil {
+		return errors.New("rate limit required")
+	}
+
+	if cmd.Op == opSetRateLimit {
+		return fsm.limits.Set(ctx, *cmd.RateLimit)
+	}
+
+	return fsm.limits.Delete(ctx, cmd.RateLimit.User)
+}
+
+func (fsm *FSM) applyNodeAddress(cmd command) error {
+	if cmd.Node == nil {
+		return errors.New("node address required")
+	}
+
+	fsm.directory.set(cmd.Node.ID, cmd.Node.Address)
+
 	return nil
 }
 
@@ -68,8 +183,28 @@ func (fsm *FSM) Snapshot() (raft.FSMSnapshot, error) {
 		return nil, err
 	}
 
+	users, err := fsm.users.Snapshot(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	tokens, err := fsm.tokens.Snapshot(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	limits, err := fsm.limits.Snapshot(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
 	return &snapshot{
-		Snapshot: snap,
+		Snapshot:   snap,
+		Users:      users,
+		JoinTokens: tokens,
+		RateLimits: limits,
+		Nodes:      fsm.directory.snapshot(),
+		events:     fsm.events,
 	}, nil
 }
 
@@ -83,5 +218,21 @@ func (fsm *FSM) Restore(reader io.ReadCloser) error {
 		return err
 	}
 
+	if err := fsm.users.Load(context.Background(), snap.Users); err != nil {
+		return err
+	}
+
+	if err := fsm.tokens.Load(context.Background(), snap.JoinTokens); err != nil {
+		return err
+	}
+
+	if err := fsm.limits.Load(context.Background(), snap.RateLimits); err != nil {
+		return err
+	}
+
+	fsm.directory.load(snap.Nodes)
+
+	fsm.events.publish(Event{Type: EventSnapshotRestored})
+
 	return nil
 }
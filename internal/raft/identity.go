@@ -0,0 +1,143 @@
+package raft
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/raft"
+	raftboltdb "github.com/hashicorp/raft-boltdb"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const nodeIDFile = "node-id"
+
+// NodeID returns stable identity of this node which does not depend on its address.
+// Identity is stored in data directory and generated on first start,
+// configured identity is used and persisted if provided.
+// Data directory of older versions has raft state without identity file, there the node
+// is a member of cluster under its address, so legacy identity is kept to match stored configuration
+func NodeID(dataLocation string, configured, legacy ServerID) (ServerID, error) {
+	path := filepath.Join(dataLocation, nodeIDFile)
+
+	stored, err := readNodeID(path)
+	if err != nil {
+		return "", err
+	}
+
+	if configured != "" {
+		if stored != "" && stored != configured {
+			return "", fmt.Errorf("configured node id %s differs from stored in %s node id %s", configured, path, stored)
+		}
+		if stored == "" {
+			if err := writeNodeID(path, configured); err != nil {
+				return "", err
+			}
+		}
+		return configured, nil
+	}
+
+	if stored != "" {
+		return stored, nil
+	}
+
+	hasState, err := hasExistingState(dataLocation)
+	if err != nil {
+		return "", err
+	}
+	if hasState && legacy != "" {
+		if err := writeNodeID(path, legacy); err != nil {
+			return "", err
+		}
+		return legacy, nil
+	}
+
+	generated, err := generateNodeID()
+	if err != nil {
+		return "", err
+	}
+
+	if err := writeNodeID(path, generated); err != nil {
+		return "", err
+	}
+
+	return generated, nil
+}
+
+// hasExistingState checks raft stores without creating them, so fresh data directory stays empty
+func hasExistingState(dataLocation string) (bool, error) {
+	logPath := filepath.Join(dataLocation, "log.db")
+	stablePath := filepath.Join(dataLocation, "stable.db")
+
+	for _, path := range []string{logPath, stablePath} {
+		_, err := os.Stat(path)
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		if err != nil {
+			return false, fmt.Errorf("cannot check raft state: %w", err)
+		}
+	}
+
+	logStore, err := raftboltdb.NewBoltStore(logPath)
+	if err != nil {
+		return false, fmt.Errorf("cannot open raft log store: %w", err)
+	}
+	defer logStore.Close()
+
+	stableStore, err := raftboltdb.NewBoltStore(stablePath)
+	if err != nil {
+		return false, fmt.Errorf("cannot open raft stable store: %w", err)
+	}
+	defer stableStore.Close()
+
+	snapshots, err := raft.NewFileSnapshotStoreWithLogger(dataLocation, 1, hclog.NewNullLogger())
+	if err != nil {
+		return false, fmt.Errorf("cannot open snapshot store: %w", err)
+	}
+
+	hasState, err := raft.HasExistingState(logStore, stableStore, snapshots)
+	if err != nil {
+		return false, fmt.Errorf("cannot check raft state: %w", err)
+	}
+
+	return hasState, nil
+}
+
+func readNodeID(path string) (ServerID, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("cannot read node id: %w", err)
+	}
+
+	return ServerID(strings.TrimSpace(string(data))), nil
+}
+
+func writeNodeID(path string, id ServerID) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("cannot create data directory: %w", err)
+	}
+
+	if err := os.WriteFile(path, []byte(id), 0o644); err != nil {
+		return fmt.Errorf("cannot write node id: %w", err)
+	}
+
+	return nil
+}
+
+func generateNodeID() (ServerID, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("cannot generate node id: %w", err)
+	}
+
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	return ServerID(fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])), nil
+}
@@ -0,0 +1,106 @@
+package raft
+
+import (
+	raftboltdb "github.com/hashicorp/raft-boltdb"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestNodeID(t *testing.T) {
+	tests := []struct {
+		name       string
+		stored     ServerID
+		raftState  bool
+		configured ServerID
+		legacy     ServerID
+		want       ServerID
+		wantErr    bool
+	}{
+		{name: "configured is persisted", configured: "node-1", legacy: "localhost:3000", want: "node-1"},
+		{name: "stored is kept", stored: "node-1", legacy: "localhost:3000", want: "node-1"},
+		{name: "configured matches stored", stored: "node-1", configured: "node-1", want: "node-1"},
+		{name: "configured differs from stored", stored: "node-1", configured: "node-2", wantErr: true},
+		{name: "legacy is kept for existing state", raftState: true, legacy: "localhost:3000", want: "localhost:3000"},
+		{name: "stored wins over legacy", stored: "node-1", raftState: true, legacy: "localhost:3000", want: "node-1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if tt.stored != "" {
+				if err := os.WriteFile(filepath.Join(dir, nodeIDFile), []byte(tt.stored+"\n"), 0o644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			if tt.raftState {
+				writeRaftState(t, dir)
+			}
+
+			got, err := NodeID(dir, tt.configured, tt.legacy)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("NodeID() = %s, want error", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("NodeID() error = %v", err)
+			}
+			if got != tt.want {
+				t.Fatalf("NodeID() = %s, want %s", got, tt.want)
+			}
+
+			again, err := NodeID(dir, "", "")
+			if err != nil {
+				t.Fatalf("NodeID() on restart error = %v", err)
+			}
+			if again != tt.want {
+				t.Fatalf("NodeID() on restart = %s, want persisted %s", again, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodeIDGenerated(t *testing.T) {
+	dir := t.TempDir()
+
+	generated, err := NodeID(dir, "", "localhost:3000")
+	if err != nil {
+		t.Fatalf("NodeID() error = %v", err)
+	}
+	if generated == "" || generated == "localhost:3000" {
+		t.Fatalf("NodeID() = %q, want generated id for fresh data directory", generated)
+	}
+	if _, err := os.Stat(filepath.Join(dir, "log.db")); !os.IsNotExist(err) {
+		t.Fatalf("NodeID() created raft stores in fresh data directory")
+	}
+
+	again, err := NodeID(dir, "", "localhost:3000")
+	if err != nil {
+		t.Fatalf("NodeID() on restart error = %v", err)
+	}
+	if again != generated {
+		t.Fatalf("NodeID() on restart = %s, want %s", again, generated)
+	}
+}
+
+func writeRaftState(t *testing.T, dir string) {
+	t.Helper()
+
+	logStore, err := raftboltdb.NewBoltStore(filepath.Join(dir, "log.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer logStore.Close()
+
+	stableStore, err := raftboltdb.NewBoltStore(filepath.Join(dir, "stable.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stableStore.Close()
+
+	if err := stableStore.SetUint64([]byte("CurrentTerm"), 1); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,35 @@
+package raft
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	proposalDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "kvstore",
+		Subsystem: "raft",
+		Name:      "proposal_duration_seconds",
+		Help:      "Time from proposing command to raft until it is applied to local state machine.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"op"})
+	proposalSize = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "kvstore",
+		Subsystem: "raft",
+		Name:      "proposal_size_bytes",
+		Help:      "Size of encoded command proposed to raft.",
+		Buckets:   prometheus.ExponentialBuckets(64, 4, 10),
+	}, []string{"op"})
+	notLeaderRejections = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kvstore",
+		Subsystem: "raft",
+		Name:      "not_leader_rejections_total",
+		Help:      "Number of requests rejected because this node is not a leader.",
+	}, []string{"op"})
+	eventsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "kvstore",
+		Subsystem: "raft",
+		Name:      "events_total",
+		Help:      "Number of cluster events seen by this node, rate of leader_changed shows flapping leadership.",
+	}, []string{"type"})
+)
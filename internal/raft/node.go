@@ -5,24 +5,64 @@ import (
 	"errors"
 	"fmt"
 	"github.com/hashicorp/raft"
+	"kvstore/internal/audit"
+	"kvstore/internal/auth"
 	"kvstore/internal/sl"
 	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultJoinTimeout = time.Minute
+	minJoinBackoff     = 200 * time.Millisecond
+	maxJoinBackoff     = 10 * time.Second
+
+	// audited changes of configuration made while accepting node besides adding it
+	auditRemoveServer        = "raft.RemoveServer"
+	auditUpdateServerAddress = "raft.UpdateServerAddress"
 )
 
 type JoinToClusterIn struct {
 	JoinerID      ServerID
 	JoinerAddress ServerAddress
+	// JoinerClientAddress is address clients of joiner connect to, it is registered in directory
+	JoinerClientAddress string
+	// JoinToken is one-time token issued by administrator
+	JoinToken string
 }
 
 type existLeader interface {
 	JoinToCluster(context context.Context, in JoinToClusterIn) error
 }
 
+type joinTokensChecker interface {
+	Check(ctx context.Context, hash string, now time.Time) error
+}
+
+type clusterStore interface {
+	UseJoinToken(ctx context.Context, secret string) error
+	SetNodeAddress(ctx context.Context, id ServerID, address string) error
+}
+
 type ClusterNodeConfig struct {
-	ID               ServerID
-	RealAddress      ServerAddress
-	Advertise        ServerAddress
+	ID          ServerID
+	RealAddress ServerAddress
+	Advertise   ServerAddress
+	// ClientAddress is advertised address clients of this node connect to
+	ClientAddress    string
 	BootstrapCluster bool
+	BootstrapExpect  int
+	JoinTimeout      time.Duration
+	// JoinToken is sent by this node when it joins to cluster
+	JoinToken sl.Secret
+	// AllowedNodes are IDs of nodes which are allowed to join, every node is allowed if it is empty,
+	// joiner must be authenticated with node certificate issued for its id
+	AllowedNodes []ServerID
+	// RequireJoinToken makes joining nodes present one-time join token
+	RequireJoinToken bool
 }
 
 type ClusterNode struct {
@@ -32,10 +72,24 @@ type ClusterNode struct {
 	id          ServerID
 	realAddress ServerAddress
 	advertise   ServerAddress
+	client      string
 	isFirstNode bool
+	joinTimeout time.Duration
+	expect      int
+	mu          *sync.Mutex
+	peers       map[ServerID]ServerAddress
+
+	joinToken        string
+	allowedNodes     []ServerID
+	requireJoinToken bool
+	store            clusterStore
+	tokens           joinTokensChecker
+	directory        *Directory
+	// joinMu serializes joins with tokens, so the same token is not accepted twice before it is used
+	joinMu *sync.Mutex
 }
 
-func NewClusterNode(logger *slog.Logger, r *raft.Raft, existLeader existLeader, conf ClusterNodeConfig) (*ClusterNode, error) {
+func NewClusterNode(logger *slog.Logger, r *raft.Raft, existLeader existLeader, store clusterStore, tokens joinTokensChecker, directory *Directory, conf ClusterNodeConfig) (*ClusterNode, error) {
 	if logger == nil {
 		return nil, errors.New("logger required")
 	}
@@ -57,9 +111,30 @@ func NewClusterNode(logger *slog.Logger, r *raft.Raft, existLeader existLeader,
 	if conf.Advertise == "" {
 		return nil, errors.New("advertised address required")
 	}
+	if conf.ClientAddress == "" {
+		return nil, errors.New("client address required")
+	}
+	if store == nil {
+		return nil, errors.New("store required")
+	}
+	if tokens == nil {
+		return nil, errors.New("tokens required")
+	}
+	if directory == nil {
+		return nil, errors.New("directory required")
+	}
 	if conf.ID == "" {
 		return nil, errors.New("nodeID required")
 	}
+	if conf.JoinTimeout <= 0 {
+		conf.JoinTimeout = defaultJoinTimeout
+	}
+	if conf.BootstrapExpect < 0 {
+		return nil, errors.New("bootstrap expect cannot be negative")
+	}
+	if conf.BootstrapExpect > 0 && conf.BootstrapCluster {
+		return nil, errors.New("bootstrap expect cannot be used with bootstrapping single node cluster")
+	}
 
 	logger.Debug("created successfully", sl.Conf(conf))
 
@@ -70,30 +145,204 @@ func NewClusterNode(logger *slog.Logger, r *raft.Raft, existLeader existLeader,
 		id:          conf.ID,
 		realAddress: conf.RealAddress,
 		advertise:   conf.Advertise,
+		client:      conf.ClientAddress,
 		isFirstNode: conf.BootstrapCluster,
+		joinTimeout: conf.JoinTimeout,
+		expect:      conf.BootstrapExpect,
+		mu:          new(sync.Mutex),
+		peers: map[ServerID]ServerAddress{
+			conf.ID: conf.Advertise,
+		},
+		joinToken:        string(conf.JoinToken),
+		allowedNodes:     conf.AllowedNodes,
+		requireJoinToken: conf.RequireJoinToken,
+		store:            store,
+		tokens:           tokens,
+		directory:        directory,
+		joinMu:           new(sync.Mutex),
 	}, nil
 }
 
 func (r *ClusterNode) AcceptJoin(ctx context.Context, in JoinToClusterIn) error {
+	if err := r.checkAllowed(ctx, in.JoinerID); err != nil {
+		r.logger.Warn("rejected node which is not allowed to join", slog.String("id", string(in.JoinerID)), sl.Error(err))
+		return err
+	}
+
+	if r.expect > 0 && !r.hasConfiguration() {
+		return r.acceptPeer(ctx, in)
+	}
+
 	if r.raft.State() != raft.Leader {
-		return newErrorIsNotLeader(r.raft)
+		return newErrorIsNotLeader(r.raft, r.directory)
+	}
+
+	future := r.raft.GetConfiguration()
+	if err := future.Error(); err != nil {
+		return fmt.Errorf("cannot get cluster configuration: %w", err)
+	}
+
+	joiner := slog.Group("joiner",
+		slog.String("id", string(in.JoinerID)),
+		slog.String("address", string(in.JoinerAddress)),
+	)
+
+	servers := future.Configuration().Servers
+
+	if slices.ContainsFunc(servers, func(server raft.Server) bool {
+		return server.ID == in.JoinerID && server.Address == in.JoinerAddress
+	}) {
+		r.logger.Debug("node is already a member of cluster", joiner)
+		r.registerAddress(ctx, in.JoinerID, in.JoinerClientAddress)
+		return nil
+	}
+
+	r.joinMu.Lock()
+	defer r.joinMu.Unlock()
+
+	if err := r.checkJoinToken(ctx, in); err != nil {
+		r.logger.Warn("rejected node without valid join token", joiner, sl.Error(err))
+		return err
+	}
+
+	var oldAddress ServerAddress
+
+	for _, server := range servers {
+		switch {
+		case server.ID == in.JoinerID:
+			// AddVoter below replaces address of the existing server
+			r.logger.Info("updating address of cluster member", joiner,
+				slog.String("old_address", string(server.Address)),
+			)
+			oldAddress = server.Address
+		case server.Address == in.JoinerAddress:
+			r.logger.Info("removing cluster member which address is taken by joiner", joiner,
+				slog.String("old_id", string(server.ID)),
+			)
+
+			removed := r.raft.RemoveServer(server.ID, 0, 0)
+			err := removed.Error()
+			audit.AddAction(ctx, audit.Action{
+				Method: auditRemoveServer,
+				Keys:   []string{string(server.ID), string(server.Address)},
+				Index:  removed.Index(),
+				Err:    err,
+			})
+			if err != nil {
+				return fmt.Errorf("cannot remove stale cluster member: %w", err)
+			}
+		}
 	}
 
-	err := r.raft.AddVoter(in.JoinerID, in.JoinerAddress, 0, 0).Error()
+	added := r.raft.AddVoter(in.JoinerID, in.JoinerAddress, 0, 0)
+	err := added.Error()
+	if oldAddress != "" {
+		audit.AddAction(ctx, audit.Action{
+			Method: auditUpdateServerAddress,
+			Keys:   []string{string(in.JoinerID), string(oldAddress), string(in.JoinerAddress)},
+			Index:  added.Index(),
+			Err:    err,
+		})
+	}
 	if err != nil {
 		return err
 	}
 
+	audit.SetIndex(ctx, added.Index())
+
+	r.logger.Info("accepted node to cluster", joiner)
+
+	// token is used only after node is added, so failed join can be retried with the same token
+	if err := r.useJoinToken(ctx, in); err != nil {
+		r.logger.Warn("cannot use join token of accepted node", joiner, sl.Error(err))
+	}
+
+	r.registerAddress(ctx, in.JoinerID, in.JoinerClientAddress)
+
 	return nil
 }
 
-func (r *ClusterNode) Run(ctx context.Context, recovered bool) error {
-	r.logger.Info("starting listening", slog.String("address", string(r.realAddress)))
+// registerAddress proposes client address of node if directory does not have it yet,
+// failure does not fail the join since node registers again on the next start
+func (r *ClusterNode) registerAddress(ctx context.Context, id ServerID, address string) {
+	if address == "" {
+		return
+	}
+	if registered, ok := r.directory.Address(id); ok && registered == address {
+		return
+	}
+
+	if err := r.store.SetNodeAddress(ctx, id, address); err != nil {
+		r.logger.Warn("cannot register client address of node",
+			slog.String("id", string(id)),
+			slog.String("address", address),
+			sl.Error(err),
+		)
+	}
+}
 
-	if recovered && r.isFirstNode {
+// RunRegistration registers client address of this node whenever it becomes the leader,
+// other nodes are registered by the leader when they join
+func (r *ClusterNode) RunRegistration(ctx context.Context) error {
+	changes := r.Changes(ctx)
+
+	for {
+		if r.raft.State() == raft.Leader {
+			r.registerAddress(ctx, r.id, r.client)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case _, ok := <-changes:
+			if !ok {
+				return ctx.Err()
+			}
+		}
+	}
+}
+
+func (r *ClusterNode) checkJoinToken(ctx context.Context, in JoinToClusterIn) error {
+	if !r.requireJoinToken {
 		return nil
 	}
 
+	if in.JoinToken == "" {
+		return fmt.Errorf("%w: join token required", ErrJoinNotAllowed)
+	}
+
+	err := r.tokens.Check(ctx, auth.HashJoinToken(in.JoinToken), time.Now())
+	if errors.Is(err, auth.ErrNoJoinToken) || errors.Is(err, auth.ErrJoinTokenExpired) {
+		return fmt.Errorf("%w: %w", ErrJoinNotAllowed, err)
+	}
+	if err != nil {
+		return fmt.Errorf("cannot check join token: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ClusterNode) useJoinToken(ctx context.Context, in JoinToClusterIn) error {
+	if !r.requireJoinToken {
+		return nil
+	}
+
+	return r.store.UseJoinToken(ctx, in.JoinToken)
+}
+
+func (r *ClusterNode) Run(ctx context.Context, hasState bool) error {
+	r.logger.Info("starting listening", slog.String("address", string(r.realAddress)))
+
+	// node with stored state is a member already, bootstrapping or joining again could split the cluster,
+	// so it only asks the cluster to update its address if the address has changed since last start
+	if hasState {
+		if r.isFirstNode || !r.addressChanged() {
+			return nil
+		}
+
+		return r.joinToCluster(ctx)
+	}
+
 	if r.isFirstNode {
 		return r.bootstrapCluster(ctx)
 	}
@@ -131,13 +380,181 @@ func (r *ClusterNode) bootstrapCluster(ctx context.Context) error {
 	return nil //todo use context to catch timeout
 }
 
-func (r *ClusterNode) joinToCluster(ctx context.Context) error {
-	err := r.existLeader.JoinToCluster(ctx, JoinToClusterIn{
-		JoinerID:      r.id,
-		JoinerAddress: r.advertise,
+// checkAllowed checks allowed nodes against name the joiner is authenticated with, e.g. name in its certificate,
+// since id in request is chosen by joiner itself, so the id must be the same as the name
+func (r *ClusterNode) checkAllowed(ctx context.Context, joinerID ServerID) error {
+	if len(r.allowedNodes) == 0 {
+		return nil
+	}
+
+	id, ok := auth.IdentityFrom(ctx)
+	if !ok || !id.Node {
+		return fmt.Errorf("%w: joiner is not authenticated as node", ErrJoinNotAllowed)
+	}
+	if !slices.Contains(r.allowedNodes, ServerID(id.User)) {
+		return fmt.Errorf("%w: %s is not in allowed nodes", ErrJoinNotAllowed, id.User)
+	}
+	if joinerID != ServerID(id.User) {
+		return fmt.Errorf("%w: %s is authenticated as %s", ErrJoinNotAllowed, joinerID, id.User)
+	}
+
+	return nil
+}
+
+// acceptPeer remembers node which expects to bootstrap cluster together with this node,
+// known peers are returned to joiner, so peers learn each other through every seed
+func (r *ClusterNode) acceptPeer(ctx context.Context, in JoinToClusterIn) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.learnPeers(map[ServerID]ServerAddress{in.JoinerID: in.JoinerAddress})
+
+	bootstrapped, err := r.tryBootstrap()
+	if err != nil {
+		return err
+	}
+	if bootstrapped {
+		return nil
+	}
+
+	return NewErrorNotBootstrapped(r.peers)
+}
+
+func (r *ClusterNode) learnPeers(peers map[ServerID]ServerAddress) {
+	for id, address := range peers {
+		if known, ok := r.peers[id]; ok && known == address {
+			continue
+		}
+
+		r.peers[id] = address
+
+		r.logger.Debug("discovered peer to bootstrap cluster",
+			slog.String("id", string(id)),
+			slog.String("address", string(address)),
+			slog.Int("known", len(r.peers)),
+			slog.Int("expected", r.expect),
+		)
+	}
+}
+
+// tryBootstrap bootstraps cluster with all known peers when expected number of them is known
+// and this node has the lowest id among them, other nodes join the cluster it bootstraps,
+// so only one initial configuration is ever written
+func (r *ClusterNode) tryBootstrap() (bool, error) {
+	if len(r.peers) < r.expect {
+		return false, nil
+	}
+
+	servers := make([]raft.Server, 0, len(r.peers))
+	for id, address := range r.peers {
+		servers = append(servers, raft.Server{
+			ID:      id,
+			Address: address,
+		})
+	}
+	slices.SortFunc(servers, func(a, b raft.Server) int {
+		return strings.Compare(string(a.ID), string(b.ID))
 	})
+
+	if servers[0].ID != r.id {
+		return false, nil
+	}
+
+	err := r.raft.BootstrapCluster(raft.Configuration{Servers: servers}).Error()
+	if errors.Is(err, raft.ErrCantBootstrap) {
+		return true, nil
+	}
 	if err != nil {
-		return fmt.Errorf("cannot join to cluster: %w", err)
+		return false, fmt.Errorf("cannot bootstrap cluster: %w", err)
+	}
+
+	r.logger.Info("bootstrapped cluster with expected peers", slog.Int("peers", len(servers)))
+
+	return true, nil
+}
+
+// bootstrapWith learns peers known by seeds and bootstraps cluster if this node is the bootstrapper
+func (r *ClusterNode) bootstrapWith(ctx context.Context, peers map[ServerID]ServerAddress) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.hasConfiguration() {
+		return false
+	}
+
+	r.learnPeers(peers)
+
+	bootstrapped, err := r.tryBootstrap()
+	if err != nil {
+		r.logger.Warn("cannot bootstrap cluster", sl.Error(err))
+	}
+
+	return bootstrapped
+}
+
+// addressChanged reports whether stored configuration has this node with other address
+func (r *ClusterNode) addressChanged() bool {
+	future := r.raft.GetConfiguration()
+	if err := future.Error(); err != nil {
+		return false
+	}
+
+	for _, server := range future.Configuration().Servers {
+		if server.ID == r.id {
+			return server.Address != r.advertise
+		}
+	}
+
+	return false
+}
+
+func (r *ClusterNode) hasConfiguration() bool {
+	future := r.raft.GetConfiguration()
+	if err := future.Error(); err != nil {
+		return false
+	}
+
+	return len(future.Configuration().Servers) > 0
+}
+
+func (r *ClusterNode) joinToCluster(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, r.joinTimeout)
+	defer cancel()
+
+	backoff := minJoinBackoff
+
+	for attempt := 1; ; attempt++ {
+		err := r.existLeader.JoinToCluster(ctx, JoinToClusterIn{
+			JoinerID:            r.id,
+			JoinerAddress:       r.advertise,
+			JoinerClientAddress: r.client,
+			JoinToken:           r.joinToken,
+		})
+		if err == nil {
+			break
+		}
+		if errors.Is(err, ErrJoinNotAllowed) {
+			return fmt.Errorf("cannot join to cluster: %w", err)
+		}
+
+		var notBootstrapped *ErrorNotBootstrapped
+		if r.expect > 0 && errors.As(err, &notBootstrapped) && r.bootstrapWith(ctx, notBootstrapped.Peers()) {
+			break
+		}
+
+		r.logger.Warn("cannot join to cluster, retrying",
+			sl.Error(err),
+			slog.Int("attempt", attempt),
+			slog.Duration("backoff", backoff),
+		)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("cannot join to cluster: %w", errors.Join(err, ctx.Err()))
+		case <-time.After(backoff):
+		}
+
+		backoff = min(backoff*2, maxJoinBackoff)
 	}
 
 	r.logger.Debug("join to cluster successfully")
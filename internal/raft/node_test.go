@@ -0,0 +1,77 @@
+package raft
+
+import (
+	"context"
+	"errors"
+	"kvstore/internal/auth"
+	"testing"
+)
+
+func TestCheckAllowed(t *testing.T) {
+	tests := []struct {
+		name     string
+		allowed  []ServerID
+		identity *auth.Identity
+		joiner   ServerID
+		wantErr  bool
+	}{
+		{name: "every node is allowed without list", joiner: "node-1"},
+		{
+			name:     "allowed node with matching certificate",
+			allowed:  []ServerID{"node-1"},
+			identity: &auth.Identity{User: "node-1", Node: true},
+			joiner:   "node-1",
+		},
+		{
+			name:     "joiner id differs from certificate",
+			allowed:  []ServerID{"node-1", "node-2"},
+			identity: &auth.Identity{User: "node-2", Node: true},
+			joiner:   "node-1",
+			wantErr:  true,
+		},
+		{
+			name:     "certificate is not in allowed nodes",
+			allowed:  []ServerID{"node-1"},
+			identity: &auth.Identity{User: "node-2", Node: true},
+			joiner:   "node-2",
+			wantErr:  true,
+		},
+		{
+			name:     "cluster secret does not tell which node joins",
+			allowed:  []ServerID{"node-1"},
+			identity: &auth.Identity{User: "node", Node: true},
+			joiner:   "node-1",
+			wantErr:  true,
+		},
+		{
+			name:     "user is not a node",
+			allowed:  []ServerID{"node-1"},
+			identity: &auth.Identity{User: "node-1"},
+			joiner:   "node-1",
+			wantErr:  true,
+		},
+		{name: "unauthenticated joiner", allowed: []ServerID{"node-1"}, joiner: "node-1", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.identity != nil {
+				ctx = auth.WithIdentity(ctx, *tt.identity)
+			}
+
+			node := &ClusterNode{allowedNodes: tt.allowed}
+
+			err := node.checkAllowed(ctx, tt.joiner)
+			if tt.wantErr {
+				if !errors.Is(err, ErrJoinNotAllowed) {
+					t.Fatalf("checkAllowed() error = %v, want %v", err, ErrJoinNotAllowed)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("checkAllowed() error = %v", err)
+			}
+		})
+	}
+}
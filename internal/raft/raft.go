@@ -1,6 +1,7 @@
 package raft
 
 import (
+	"crypto/tls"
 	"fmt"
 	"github.com/hashicorp/go-hclog"
 	"github.com/hashicorp/raft"
@@ -12,6 +13,17 @@ import (
 	"time"
 )
 
+type Preset string
+
+const (
+	// PresetLAN is hashicorp/raft defaults which are tuned for nodes in the same network
+	PresetLAN Preset = "lan"
+	// PresetWAN is PresetLAN with timeouts relaxed for nodes with high latency between them
+	PresetWAN Preset = "wan"
+)
+
+const wanMultiplier = 5
+
 type Config struct {
 	RealAddress       string
 	AdvertisedAddress string
@@ -20,16 +32,47 @@ type Config struct {
 	SnapshotsRetain   int
 	MaxPool           int
 	TCPTimeout        time.Duration
+	// Recover overwrites stored configuration with this node only, it is manual recovery of lost quorum
+	Recover bool
+	Tuning  Tuning
+}
+
+// Tuning overrides timings of the chosen preset, zero values keep preset ones
+type Tuning struct {
+	Preset             Preset
+	HeartbeatTimeout   time.Duration
+	ElectionTimeout    time.Duration
+	LeaderLeaseTimeout time.Duration
+	CommitTimeout      time.Duration
+	SnapshotInterval   time.Duration
+	SnapshotThreshold  uint64
+	TrailingLogs       uint64
+	MaxAppendEntries   int
+	BatchApply         bool
 }
 
-func New(logger *slog.Logger, hcLogger hclog.Logger, fsm raft.FSM, conf Config) (*raft.Raft, bool, error) {
+func New(logger *slog.Logger, hcLogger hclog.Logger, fsm raft.FSM, transport raft.Transport, events *Events, conf Config) (*raft.Raft, bool, error) {
 	logger = logger.With(sl.Component("raft.New"))
 	logger.Debug("creating raft instance", sl.Conf(conf))
 
-	raftConfig := raft.DefaultConfig()
+	if transport == nil {
+		return nil, false, fmt.Errorf("transport required")
+	}
+	if events == nil {
+		return nil, false, fmt.Errorf("events required")
+	}
+
+	raftConfig, err := newRaftConfig(conf.Tuning)
+	if err != nil {
+		return nil, false, err
+	}
 	raftConfig.LocalID = ServerID(conf.NodeID)
 	raftConfig.Logger = hcLogger
 
+	if err := raft.ValidateConfig(raftConfig); err != nil {
+		return nil, false, fmt.Errorf("invalid raft config: %w", err)
+	}
+
 	logStore, err := raftboltdb.NewBoltStore(filepath.Join(conf.DataLocation, "log.db"))
 	if err != nil {
 		return nil, false, fmt.Errorf("cannnot create raft log store: %v", err)
@@ -45,22 +88,14 @@ func New(logger *slog.Logger, hcLogger hclog.Logger, fsm raft.FSM, conf Config)
 		return nil, false, fmt.Errorf("cannot create snapshot store: %v", err)
 	}
 
-	advertisedAddr, err := net.ResolveTCPAddr("tcp", conf.AdvertisedAddress)
-	if err != nil {
-		return nil, false, fmt.Errorf("cannot resolve advertised address: %v", err)
-	}
-
-	transport, err := raft.NewTCPTransportWithLogger(conf.RealAddress, advertisedAddr, conf.MaxPool, conf.TCPTimeout, hcLogger)
-	if err != nil {
-		return nil, false, fmt.Errorf("cannot create transport: %v", err)
-	}
-
 	hasState, err := raft.HasExistingState(logStore, stableStore, snapshots)
 	if err != nil {
 		return nil, false, fmt.Errorf("cannot check existing state: %v", err)
 	}
 
-	if hasState { //todo это должно быть в другом месте
+	if hasState && conf.Recover {
+		logger.Warn("recovering cluster with this node only")
+
 		err := raft.RecoverCluster(raftConfig, fsm, logStore, stableStore, snapshots, transport, raft.Configuration{
 			Servers: []raft.Server{
 				{
@@ -80,7 +115,72 @@ func New(logger *slog.Logger, hcLogger hclog.Logger, fsm raft.FSM, conf Config)
 		return nil, false, fmt.Errorf("cannot create raft.Raft r: %v", err)
 	}
 
+	events.observe(r)
+
 	logger.Debug("created successfully", sl.Conf(conf))
 
 	return r, hasState, nil
 }
+
+// NewTCPTransport creates raft transport which listens on separate internal port,
+// connections between nodes are wrapped into tls if tls configs are provided
+func NewTCPTransport(hcLogger hclog.Logger, serverTLS, clientTLS *tls.Config, conf Config) (raft.Transport, error) {
+	if conf.TCPTimeout <= 0 {
+		return nil, fmt.Errorf("tcp timeout must be positive")
+	}
+
+	advertisedAddr, err := net.ResolveTCPAddr("tcp", conf.AdvertisedAddress)
+	if err != nil {
+		return nil, fmt.Errorf("cannot resolve advertised address: %v", err)
+	}
+
+	if serverTLS == nil || clientTLS == nil {
+		transport, err := raft.NewTCPTransportWithLogger(conf.RealAddress, advertisedAddr, conf.MaxPool, conf.TCPTimeout, hcLogger)
+		if err != nil {
+			return nil, fmt.Errorf("cannot create transport: %v", err)
+		}
+
+		return transport, nil
+	}
+
+	stream, err := newTLSStreamLayer(conf.RealAddress, advertisedAddr, serverTLS, clientTLS)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create tls stream layer: %v", err)
+	}
+
+	return raft.NewNetworkTransportWithLogger(stream, conf.MaxPool, conf.TCPTimeout, hcLogger), nil
+}
+
+func newRaftConfig(tuning Tuning) (*raft.Config, error) {
+	conf := raft.DefaultConfig()
+
+	switch tuning.Preset {
+	case PresetLAN, "":
+	case PresetWAN:
+		conf.HeartbeatTimeout *= wanMultiplier
+		conf.ElectionTimeout *= wanMultiplier
+		conf.LeaderLeaseTimeout *= wanMultiplier
+	default:
+		return nil, fmt.Errorf("unknown raft preset %q", tuning.Preset)
+	}
+
+	override(&conf.HeartbeatTimeout, tuning.HeartbeatTimeout)
+	override(&conf.ElectionTimeout, tuning.ElectionTimeout)
+	override(&conf.LeaderLeaseTimeout, tuning.LeaderLeaseTimeout)
+	override(&conf.CommitTimeout, tuning.CommitTimeout)
+	override(&conf.SnapshotInterval, tuning.SnapshotInterval)
+	override(&conf.SnapshotThreshold, tuning.SnapshotThreshold)
+	override(&conf.TrailingLogs, tuning.TrailingLogs)
+	override(&conf.MaxAppendEntries, tuning.MaxAppendEntries)
+
+	conf.BatchApplyCh = tuning.BatchApply
+
+	return conf, nil
+}
+
+func override[T comparable](target *T, value T) {
+	var zero T
+	if value != zero {
+		*target = value
+	}
+}
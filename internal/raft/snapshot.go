@@ -4,11 +4,20 @@ import (
 	"encoding/json"
 	"errors"
 	"github.com/hashicorp/raft"
+	"kvstore/internal/auth"
 	"kvstore/internal/core"
+	"kvstore/internal/ratelimit"
 )
 
 type snapshot struct {
 	core.Snapshot
+	Users      map[string]auth.User
+	JoinTokens map[string]auth.JoinToken
+	RateLimits map[string]ratelimit.Limit
+	Nodes      map[ServerID]string
+
+	// events are notified once snapshot is written, since raft may fail to persist it
+	events *Events
 }
 
 func (s *snapshot) Persist(sink raft.SnapshotSink) (err error) {
@@ -23,9 +32,13 @@ func (s *snapshot) Persist(sink raft.SnapshotSink) (err error) {
 		if closeErr := closeFunc(); closeErr != nil {
 			err = errors.Join(err, closeErr)
 		}
+
+		if err == nil {
+			s.events.publish(Event{Type: EventSnapshotCreated})
+		}
 	}()
 
-	data, err := json.Marshal(s.Snapshot)
+	data, err := json.Marshal(s)
 	if err != nil {
 		return err
 	}
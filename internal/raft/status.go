@@ -0,0 +1,97 @@
+package raft
+
+import (
+	"context"
+	"github.com/hashicorp/raft"
+	"strconv"
+)
+
+const changesBuffer = 16
+
+// StateShutdown is state of node which raft is shut down
+var StateShutdown = raft.Shutdown.String()
+
+// Status is a view of raft state of this node
+type Status struct {
+	ID                ServerID      `json:"id"`
+	State             string        `json:"state"`
+	LeaderID          ServerID      `json:"leader_id"`
+	LeaderAddress     ServerAddress `json:"leader_address"`
+	Term              uint64        `json:"term"`
+	AppliedIndex      uint64        `json:"applied_index"`
+	CommitIndex       uint64        `json:"commit_index"`
+	LastIndex         uint64        `json:"last_index"`
+	LastSnapshotIndex uint64        `json:"last_snapshot_index"`
+	LastSnapshotTerm  uint64        `json:"last_snapshot_term"`
+}
+
+func (r *ClusterNode) Status() Status {
+	stats := r.raft.Stats()
+	leaderAddress, leaderID := r.raft.LeaderWithID()
+
+	return Status{
+		ID:                r.id,
+		State:             r.raft.State().String(),
+		LeaderID:          leaderID,
+		LeaderAddress:     leaderAddress,
+		Term:              statsUint(stats, "term"),
+		AppliedIndex:      r.raft.AppliedIndex(),
+		CommitIndex:       statsUint(stats, "commit_index"),
+		LastIndex:         r.raft.LastIndex(),
+		LastSnapshotIndex: statsUint(stats, "last_snapshot_index"),
+		LastSnapshotTerm:  statsUint(stats, "last_snapshot_term"),
+	}
+}
+
+// Ready reports whether node is a member of cluster which has a leader
+func (r *ClusterNode) Ready() bool {
+	if r.raft.State() == raft.Shutdown {
+		return false
+	}
+
+	leaderAddress, _ := r.raft.LeaderWithID()
+
+	return leaderAddress != ""
+}
+
+// Changes notifies about changes of raft state, leader and peers until context is done,
+// notifications are coalesced, so receiver should read status again on every one of them
+func (r *ClusterNode) Changes(ctx context.Context) <-chan struct{} {
+	observations := make(chan raft.Observation, changesBuffer)
+	observer := raft.NewObserver(observations, false, func(o *raft.Observation) bool {
+		switch o.Data.(type) {
+		case raft.LeaderObservation, raft.RaftState, raft.PeerObservation:
+			return true
+		default:
+			return false
+		}
+	})
+
+	r.raft.RegisterObserver(observer)
+
+	changes := make(chan struct{}, 1)
+
+	go func() {
+		defer close(changes)
+		defer r.raft.DeregisterObserver(observer)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-observations:
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+
+	return changes
+}
+
+func statsUint(stats map[string]string, key string) uint64 {
+	value, _ := strconv.ParseUint(stats[key], 10, 64)
+	return value
+}
@@ -6,20 +6,32 @@ import (
 	"errors"
 	"fmt"
 	"github.com/hashicorp/raft"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"kvstore/internal/audit"
+	"kvstore/internal/auth"
 	"kvstore/internal/core"
+	"kvstore/internal/ratelimit"
 	"kvstore/internal/sl"
+	"kvstore/internal/slowlog"
 	"log/slog"
 	"time"
 )
 
 // Store make some key value storage distributed via raft
 type Store struct {
-	logger *slog.Logger
-	raft   *raft.Raft
-	store  kvstore
+	logger    *slog.Logger
+	raft      *raft.Raft
+	store     kvstore
+	sizes     core.SizeLimits
+	slow      slowLog
+	directory *Directory
 }
 
-func NewStore(logger *slog.Logger, raft *raft.Raft, store kvstore) (*Store, error) {
+func NewStore(logger *slog.Logger, raft *raft.Raft, store kvstore, directory *Directory, slow slowLog, sizes core.SizeLimits) (*Store, error) {
 	if logger == nil {
 		return nil, errors.New("logger required")
 	}
@@ -32,13 +44,22 @@ func NewStore(logger *slog.Logger, raft *raft.Raft, store kvstore) (*Store, erro
 	if store == nil {
 		return nil, errors.New("store required")
 	}
+	if directory == nil {
+		return nil, errors.New("directory required")
+	}
+	if slow == nil {
+		return nil, errors.New("slow log required")
+	}
 
-	logger.Debug("created successfully")
+	logger.Debug("created successfully", sl.Conf(sizes))
 
 	return &Store{
-		logger: logger,
-		raft:   raft,
-		store:  store,
+		logger:    logger,
+		raft:      raft,
+		store:     store,
+		sizes:     sizes,
+		slow:      slow,
+		directory: directory,
 	}, nil
 }
 
@@ -48,15 +69,29 @@ func (s *Store) Get(ctx context.Context, key core.Key) (*core.Value, error) {
 
 func (s *Store) ConsistentGet(ctx context.Context, key core.Key) (*core.Value, error) {
 	if s.raft.State() != raft.Leader {
-		return nil, newErrorIsNotLeader(s.raft)
+		return nil, s.notLeader("consistent_get")
 	}
 
 	return s.store.Get(ctx, key)
 }
 
 func (s *Store) Put(ctx context.Context, key core.Key, value core.Value, ttl time.Duration) error {
+	if err := s.sizes.CheckEntry(key, value); err != nil {
+		return err
+	}
+
 	if s.raft.State() != raft.Leader {
-		return newErrorIsNotLeader(s.raft)
+		return s.notLeader(opPut)
+	}
+
+	var owner string
+	if id, ok := auth.IdentityFrom(ctx); ok {
+		owner = id.User
+	}
+
+	// write which exceeds quota is rejected before it is proposed, it is checked again when applied
+	if err := s.store.CheckQuota(ctx, key, value, owner); err != nil {
+		return err
 	}
 
 	return s.apply(ctx, command{
@@ -64,12 +99,13 @@ func (s *Store) Put(ctx context.Context, key core.Key, value core.Value, ttl tim
 		Key:   key,
 		Value: value,
 		TTL:   ttl,
+		Owner: owner,
 	})
 }
 
 func (s *Store) Delete(ctx context.Context, key core.Key) error {
-	if s.raft.VerifyLeader().Error() != nil {
-		return newErrorIsNotLeader(s.raft)
+	if err := s.verifyLeader(ctx); err != nil {
+		return s.notLeader(opDelete)
 	}
 
 	return s.apply(ctx, command{
@@ -78,6 +114,81 @@ func (s *Store) Delete(ctx context.Context, key core.Key) error {
 	})
 }
 
+func (s *Store) CreateUser(ctx context.Context, user auth.User) error {
+	return s.applyUser(ctx, opCreateUser, user)
+}
+
+// SetPasswordHash replaces only password hash, roles are taken from the record at the time of applying
+func (s *Store) SetPasswordHash(ctx context.Context, name string, hash []byte) error {
+	return s.applyUser(ctx, opSetPasswordHash, auth.User{Name: name, PasswordHash: hash})
+}
+
+func (s *Store) DeleteUser(ctx context.Context, name string) error {
+	return s.applyUser(ctx, opDeleteUser, auth.User{Name: name})
+}
+
+func (s *Store) applyUser(ctx context.Context, op operation, user auth.User) error {
+	if s.raft.State() != raft.Leader {
+		return s.notLeader(op)
+	}
+
+	return s.apply(ctx, command{
+		Op:   op,
+		User: &user,
+	})
+}
+
+func (s *Store) CreateJoinToken(ctx context.Context, token auth.JoinToken) error {
+	return s.applyJoinToken(ctx, opCreateJoinToken, token)
+}
+
+// UseJoinToken consumes token, so it cannot be used again
+func (s *Store) UseJoinToken(ctx context.Context, secret string) error {
+	return s.applyJoinToken(ctx, opUseJoinToken, auth.JoinToken{Hash: auth.HashJoinToken(secret)})
+}
+
+func (s *Store) applyJoinToken(ctx context.Context, op operation, token auth.JoinToken) error {
+	if s.raft.State() != raft.Leader {
+		return s.notLeader(op)
+	}
+
+	return s.apply(ctx, command{
+		Op:        op,
+		JoinToken: &token,
+	})
+}
+
+func (s *Store) SetRateLimit(ctx context.Context, limit ratelimit.UserLimit) error {
+	return s.applyRateLimit(ctx, opSetRateLimit, limit)
+}
+
+func (s *Store) DeleteRateLimit(ctx context.Context, user string) error {
+	return s.applyRateLimit(ctx, opDeleteRateLimit, ratelimit.UserLimit{User: user})
+}
+
+func (s *Store) applyRateLimit(ctx context.Context, op operation, limit ratelimit.UserLimit) error {
+	if s.raft.State() != raft.Leader {
+		return s.notLeader(op)
+	}
+
+	return s.apply(ctx, command{
+		Op:        op,
+		RateLimit: &limit,
+	})
+}
+
+// SetNodeAddress registers client address of node in directory
+func (s *Store) SetNodeAddress(ctx context.Context, id ServerID, address string) error {
+	if s.raft.State() != raft.Leader {
+		return s.notLeader(opSetNodeAddress)
+	}
+
+	return s.apply(ctx, command{
+		Op:   opSetNodeAddress,
+		Node: &nodeAddress{ID: id, Address: address},
+	})
+}
+
 func (s *Store) RunCleaning(ctx context.Context) error {
 	for {
 		select {
@@ -100,12 +211,54 @@ func (s *Store) RunCleaning(ctx context.Context) error {
 	}
 }
 
-func (s *Store) apply(ctx context.Context, cmd command) error {
+func (s *Store) notLeader(op operation) error {
+	notLeaderRejections.WithLabelValues(string(op)).Inc()
+
+	return newErrorIsNotLeader(s.raft, s.directory)
+}
+
+func (s *Store) verifyLeader(ctx context.Context) error {
+	_, span := tracer.Start(ctx, "raft.VerifyLeader")
+	defer span.End()
+
+	defer slowlog.Phase(ctx, slowlog.PhaseLeaderCheck)()
+
+	if err := s.raft.VerifyLeader().Error(); err != nil {
+		span.SetStatus(codes.Error, err.Error())
+		return err
+	}
+
+	return nil
+}
+
+func (s *Store) apply(ctx context.Context, cmd command) (err error) {
+	ctx, span := tracer.Start(ctx, "raft.Apply", trace.WithAttributes(attribute.String("op", string(cmd.Op))))
+	ctx, done := s.slow.Begin(ctx, "raft apply "+string(cmd.Op))
+	defer func() {
+		if err != nil {
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+		done(err)
+	}()
+
+	cmd.Trace = propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, cmd.Trace)
+
 	bytes, err := json.Marshal(cmd)
 	if err != nil {
 		return err
 	}
 
+	span.SetAttributes(attribute.Int("size", len(bytes)))
+
+	proposalSize.WithLabelValues(string(cmd.Op)).Observe(float64(len(bytes)))
+
+	start := time.Now()
+	defer func() {
+		proposalDuration.WithLabelValues(string(cmd.Op)).Observe(time.Since(start).Seconds())
+	}()
+
 	var timeout time.Duration
 
 	deadline, ok := ctx.Deadline()
@@ -113,11 +266,22 @@ func (s *Store) apply(ctx context.Context, cmd command) error {
 		timeout = time.Until(deadline)
 	}
 
-	err = s.raft.Apply(bytes, timeout).Error()
-	if err != nil {
+	future := s.raft.Apply(bytes, timeout)
+	if err := future.Error(); err != nil {
 		return fmt.Errorf("appling log to other nodes: %w", err)
 	}
 
+	audit.SetIndex(ctx, future.Index())
+
+	resp, _ := future.Response().(applied)
+
+	slowlog.Observe(ctx, slowlog.PhaseRaftCommit, time.Since(start)-resp.duration)
+	slowlog.Observe(ctx, slowlog.PhaseFSMApply, resp.duration)
+
+	if resp.err != nil {
+		return resp.err
+	}
+
 	s.logger.Debug("applied command", cmd.LogAttr())
 
 	return nil
@@ -0,0 +1,37 @@
+package raft
+
+import (
+	"crypto/tls"
+	"github.com/hashicorp/raft"
+	"net"
+	"time"
+)
+
+// tlsStreamLayer is raft.StreamLayer which wraps connections between nodes into tls
+type tlsStreamLayer struct {
+	net.Listener
+	advertise net.Addr
+	clientTLS *tls.Config
+}
+
+func newTLSStreamLayer(address string, advertise net.Addr, serverTLS, clientTLS *tls.Config) (*tlsStreamLayer, error) {
+	listener, err := tls.Listen("tcp", address, serverTLS)
+	if err != nil {
+		return nil, err
+	}
+
+	return &tlsStreamLayer{
+		Listener:  listener,
+		advertise: advertise,
+		clientTLS: clientTLS,
+	}, nil
+}
+
+func (s *tlsStreamLayer) Dial(address raft.ServerAddress, timeout time.Duration) (net.Conn, error) {
+	dialer := &net.Dialer{Timeout: timeout}
+	return tls.DialWithDialer(dialer, "tcp", string(address), s.clientTLS)
+}
+
+func (s *tlsStreamLayer) Addr() net.Addr {
+	return s.advertise
+}
@@ -0,0 +1,209 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"golang.org/x/time/rate"
+	"kvstore/internal/sl"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const defaultCleanInterval = time.Minute
+
+type Limit struct {
+	// Rate is amount of requests per second, requests are not limited if it is zero
+	Rate  float64 `json:"rate"`
+	Burst int     `json:"burst"`
+}
+
+func (l Limit) limited() bool {
+	return l.Rate > 0
+}
+
+type Config struct {
+	// Default is limit of every caller which has no own limit
+	Default Limit
+	// Users are limits of particular users, limits replicated via raft take precedence over them
+	Users map[string]Limit
+	// Methods are limits of every caller for particular methods, they are applied in addition to caller limits
+	Methods map[string]Limit
+	// Address is limit of every client address checked before authentication, so failing
+	// authentication attempts which run bcrypt are limited too
+	Address       Limit
+	CleanInterval time.Duration
+}
+
+type overrides interface {
+	Get(ctx context.Context, user string) (Limit, bool)
+}
+
+type bucketKey struct {
+	caller string
+	// method is empty for bucket which limits all requests of caller
+	method string
+}
+
+type bucket struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+// Limiter keeps token bucket per caller and per caller and method
+type Limiter struct {
+	logger        *slog.Logger
+	defaultLimit  Limit
+	users         map[string]Limit
+	methods       map[string]Limit
+	address       Limit
+	overrides     overrides
+	cleanInterval time.Duration
+
+	mu      *sync.Mutex
+	buckets map[bucketKey]*bucket
+}
+
+func New(logger *slog.Logger, overrides overrides, conf Config) (*Limiter, error) {
+	if logger == nil {
+		return nil, errors.New("logger required")
+	}
+
+	logger = logger.With(sl.Component("ratelimit.Limiter"))
+
+	if overrides == nil {
+		return nil, errors.New("overrides required")
+	}
+	if conf.CleanInterval <= 0 {
+		conf.CleanInterval = defaultCleanInterval
+	}
+
+	logger.Debug("created successfully", sl.Conf(conf))
+
+	return &Limiter{
+		logger:        logger,
+		defaultLimit:  conf.Default,
+		users:         conf.Users,
+		methods:       conf.Methods,
+		address:       conf.Address,
+		overrides:     overrides,
+		cleanInterval: conf.CleanInterval,
+		mu:            new(sync.Mutex),
+		buckets:       make(map[bucketKey]*bucket),
+	}, nil
+}
+
+type limited struct {
+	key   bucketKey
+	limit Limit
+	name  string
+}
+
+// Allow takes token from buckets of caller, if some bucket is empty
+// nothing is taken and the time after which request can be retried is returned
+func (l *Limiter) Allow(ctx context.Context, caller, user, method string) (time.Duration, bool) {
+	return l.allow(time.Now(), method,
+		limited{key: bucketKey{caller: caller}, limit: l.userLimit(ctx, user), name: "caller"},
+		limited{key: bucketKey{caller: caller, method: method}, limit: l.methods[method], name: "method"},
+	)
+}
+
+// AllowAddress takes token from bucket of client address, it is cheap enough to be checked before authentication
+func (l *Limiter) AllowAddress(_ context.Context, address, method string) (time.Duration, bool) {
+	// buckets of addresses are separate from buckets of unauthenticated callers checked by Allow
+	return l.allow(time.Now(), method,
+		limited{key: bucketKey{caller: "peer:" + address}, limit: l.address, name: "address"},
+	)
+}
+
+func (l *Limiter) allow(now time.Time, method string, limits ...limited) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	var reservations []*rate.Reservation
+	cancel := func() {
+		for _, reservation := range reservations {
+			reservation.CancelAt(now)
+		}
+	}
+
+	for _, limited := range limits {
+		if !limited.limit.limited() {
+			continue
+		}
+
+		// reservation of one token is always ok since burst is at least one
+		reservation := l.bucket(limited.key, limited.limit, now).ReserveN(now, 1)
+		if delay := reservation.DelayFrom(now); delay > 0 {
+			reservation.CancelAt(now)
+			cancel()
+			throttledRequests.WithLabelValues(method, limited.name).Inc()
+			return delay, false
+		}
+
+		reservations = append(reservations, reservation)
+	}
+
+	return 0, true
+}
+
+// Run forgets buckets of callers which have not sent requests for clean interval
+func (l *Limiter) Run(ctx context.Context) error {
+	ticker := time.NewTicker(l.cleanInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+
+		l.clean(time.Now())
+	}
+}
+
+func (l *Limiter) userLimit(ctx context.Context, user string) Limit {
+	if user == "" {
+		return l.defaultLimit
+	}
+	if limit, ok := l.overrides.Get(ctx, user); ok {
+		return limit
+	}
+	if limit, ok := l.users[user]; ok {
+		return limit
+	}
+	return l.defaultLimit
+}
+
+func (l *Limiter) bucket(key bucketKey, limit Limit, now time.Time) *rate.Limiter {
+	burst := max(limit.Burst, 1)
+
+	// bucket is replaced with a full one if limit is changed by replicated overrides
+	b, ok := l.buckets[key]
+	if !ok || b.limiter.Limit() != rate.Limit(limit.Rate) || b.limiter.Burst() != burst {
+		b = &bucket{
+			limiter: rate.NewLimiter(rate.Limit(limit.Rate), burst),
+		}
+		l.buckets[key] = b
+	}
+
+	b.lastSeen = now
+
+	return b.limiter
+}
+
+func (l *Limiter) clean(now time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	before := len(l.buckets)
+
+	for key, b := range l.buckets {
+		if now.Sub(b.lastSeen) > l.cleanInterval {
+			delete(l.buckets, key)
+		}
+	}
+
+	l.logger.Debug("cleaned idle buckets", slog.Int("removed", before-len(l.buckets)), slog.Int("left", len(l.buckets)))
+}
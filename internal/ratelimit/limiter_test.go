@@ -0,0 +1,140 @@
+package ratelimit
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+)
+
+// slow is refilled too slowly to get a token back during test
+const slow = 0.001
+
+type call struct {
+	caller  string
+	user    string
+	method  string
+	address bool
+	want    bool
+}
+
+func TestLimiter(t *testing.T) {
+	tests := []struct {
+		name      string
+		conf      Config
+		overrides map[string]Limit
+		calls     []call
+	}{
+		{
+			name: "zero rate is not limited",
+			calls: []call{
+				{caller: "user:a", user: "a", method: "/get", want: true},
+				{caller: "user:a", user: "a", method: "/get", want: true},
+				{caller: "1.1.1.1", method: "/get", address: true, want: true},
+			},
+		},
+		{
+			name: "default limit per caller",
+			conf: Config{Default: Limit{Rate: slow, Burst: 1}},
+			calls: []call{
+				{caller: "user:a", user: "a", method: "/get", want: true},
+				{caller: "user:a", user: "a", method: "/put", want: false},
+				{caller: "user:b", user: "b", method: "/get", want: true},
+			},
+		},
+		{
+			name: "user limit takes precedence over default",
+			conf: Config{
+				Default: Limit{Rate: slow, Burst: 1},
+				Users:   map[string]Limit{"a": {Rate: slow, Burst: 2}},
+			},
+			calls: []call{
+				{caller: "user:a", user: "a", method: "/get", want: true},
+				{caller: "user:a", user: "a", method: "/get", want: true},
+				{caller: "user:a", user: "a", method: "/get", want: false},
+			},
+		},
+		{
+			name: "override takes precedence over user limit",
+			conf: Config{
+				Users: map[string]Limit{"a": {Rate: slow, Burst: 2}},
+			},
+			overrides: map[string]Limit{"a": {Rate: slow, Burst: 1}},
+			calls: []call{
+				{caller: "user:a", user: "a", method: "/get", want: true},
+				{caller: "user:a", user: "a", method: "/get", want: false},
+			},
+		},
+		{
+			name: "caller token is returned if method bucket is empty",
+			conf: Config{
+				Default: Limit{Rate: slow, Burst: 2},
+				Methods: map[string]Limit{"/put": {Rate: slow, Burst: 1}},
+			},
+			calls: []call{
+				{caller: "user:a", user: "a", method: "/put", want: true},
+				{caller: "user:a", user: "a", method: "/put", want: false},
+				{caller: "user:a", user: "a", method: "/get", want: true},
+				{caller: "user:a", user: "a", method: "/get", want: false},
+			},
+		},
+		{
+			name: "method limit is per caller",
+			conf: Config{
+				Methods: map[string]Limit{"/put": {Rate: slow, Burst: 1}},
+			},
+			calls: []call{
+				{caller: "user:a", user: "a", method: "/put", want: true},
+				{caller: "user:b", user: "b", method: "/put", want: true},
+				{caller: "user:a", user: "a", method: "/put", want: false},
+			},
+		},
+		{
+			name: "address buckets are separate from caller ones",
+			conf: Config{
+				Default: Limit{Rate: slow, Burst: 1},
+				Address: Limit{Rate: slow, Burst: 1},
+			},
+			calls: []call{
+				{caller: "1.1.1.1", method: "/get", address: true, want: true},
+				{caller: "address:1.1.1.1", method: "/get", want: true},
+				{caller: "1.1.1.1", method: "/get", address: true, want: false},
+				{caller: "2.2.2.2", method: "/get", address: true, want: true},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			overrides := NewOverrides()
+			if err := overrides.Load(context.Background(), tt.overrides); err != nil {
+				t.Fatal(err)
+			}
+
+			limiter, err := New(slog.New(slog.DiscardHandler), overrides, tt.conf)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i, c := range tt.calls {
+				allow := func() (time.Duration, bool) {
+					return limiter.Allow(context.Background(), c.caller, c.user, c.method)
+				}
+				if c.address {
+					allow = func() (time.Duration, bool) {
+						return limiter.AllowAddress(context.Background(), c.caller, c.method)
+					}
+				}
+
+				retryAfter, ok := allow()
+
+				if ok != c.want {
+					t.Fatalf("call %d: allowed = %v, want %v", i, ok, c.want)
+				}
+				if !ok && retryAfter <= 0 {
+					t.Fatalf("call %d: retry after = %s, want positive", i, retryAfter)
+				}
+			}
+		})
+	}
+}
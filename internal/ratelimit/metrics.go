@@ -0,0 +1,13 @@
+package ratelimit
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var throttledRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+	Namespace: "kvstore",
+	Subsystem: "ratelimit",
+	Name:      "throttled_requests_total",
+	Help:      "Number of requests rejected by rate limiter, limit is caller, method or address one.",
+}, []string{"method", "limit"})
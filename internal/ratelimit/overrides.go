@@ -0,0 +1,71 @@
+package ratelimit
+
+import (
+	"context"
+	"maps"
+	"sync"
+)
+
+// UserLimit is limit of user which is replicated via raft to apply on every node
+type UserLimit struct {
+	User  string `json:"user"`
+	Limit Limit  `json:"limit"`
+}
+
+// Overrides are replicated limits of users, they are changed only by FSM
+type Overrides struct {
+	mu     *sync.RWMutex
+	limits map[string]Limit
+}
+
+func NewOverrides() *Overrides {
+	return &Overrides{
+		mu:     new(sync.RWMutex),
+		limits: make(map[string]Limit),
+	}
+}
+
+func (o *Overrides) Get(_ context.Context, user string) (Limit, bool) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	limit, ok := o.limits[user]
+	return limit, ok
+}
+
+func (o *Overrides) Set(_ context.Context, limit UserLimit) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.limits[limit.User] = limit.Limit
+
+	return nil
+}
+
+func (o *Overrides) Delete(_ context.Context, user string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	delete(o.limits, user)
+
+	return nil
+}
+
+func (o *Overrides) Snapshot(_ context.Context) (map[string]Limit, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	return maps.Clone(o.limits), nil
+}
+
+func (o *Overrides) Load(_ context.Context, limits map[string]Limit) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if limits == nil {
+		limits = make(map[string]Limit)
+	}
+	o.limits = limits
+
+	return nil
+}
@@ -0,0 +1,184 @@
+package sl
+
+import (
+	"context"
+	"fmt"
+	"github.com/hashicorp/go-hclog"
+	"io"
+	"log"
+	"log/slog"
+	"slices"
+)
+
+// LevelTrace is level of hclog trace messages which slog has no own level for
+const LevelTrace = slog.LevelDebug - 4
+
+// HCLogger is hclog.Logger backed by slog.Logger, so logs of hashicorp libraries
+// share handler, output and levels with ours, name of the logger is its component
+type HCLogger struct {
+	base    *slog.Logger
+	logger  *slog.Logger
+	levels  *Levels
+	name    string
+	implied []any
+}
+
+// NewHCLogger creates adapter, levels are the ones which handler of logger filters records with,
+// SetLevel changes level of the component of adapter
+func NewHCLogger(logger *slog.Logger, levels *Levels, name string) *HCLogger {
+	return &HCLogger{
+		base:   logger,
+		logger: logger.With(Component(name)),
+		levels: levels,
+		name:   name,
+	}
+}
+
+func (l *HCLogger) Log(level hclog.Level, msg string, args ...any) {
+	if level == hclog.Off || level == hclog.NoLevel {
+		return
+	}
+
+	slogLevel := toSlogLevel(level)
+
+	ctx := context.Background()
+	if !l.logger.Enabled(ctx, slogLevel) {
+		return
+	}
+
+	attrs := make([]slog.Attr, 0, (len(l.implied)+len(args))/2)
+	attrs = appendArgs(attrs, l.implied)
+	attrs = appendArgs(attrs, args)
+
+	l.logger.LogAttrs(ctx, slogLevel, msg, attrs...)
+}
+
+func (l *HCLogger) Trace(msg string, args ...any) { l.Log(hclog.Trace, msg, args...) }
+
+func (l *HCLogger) Debug(msg string, args ...any) { l.Log(hclog.Debug, msg, args...) }
+
+func (l *HCLogger) Info(msg string, args ...any) { l.Log(hclog.Info, msg, args...) }
+
+func (l *HCLogger) Warn(msg string, args ...any) { l.Log(hclog.Warn, msg, args...) }
+
+func (l *HCLogger) Error(msg string, args ...any) { l.Log(hclog.Error, msg, args...) }
+
+func (l *HCLogger) IsTrace() bool { return l.enabled(hclog.Trace) }
+
+func (l *HCLogger) IsDebug() bool { return l.enabled(hclog.Debug) }
+
+func (l *HCLogger) IsInfo() bool { return l.enabled(hclog.Info) }
+
+func (l *HCLogger) IsWarn() bool { return l.enabled(hclog.Warn) }
+
+func (l *HCLogger) IsError() bool { return l.enabled(hclog.Error) }
+
+func (l *HCLogger) ImpliedArgs() []any {
+	return slices.Clone(l.implied)
+}
+
+func (l *HCLogger) With(args ...any) hclog.Logger {
+	sub := *l
+	sub.implied = append(slices.Clone(l.implied), args...)
+	return &sub
+}
+
+func (l *HCLogger) Name() string {
+	return l.name
+}
+
+func (l *HCLogger) Named(name string) hclog.Logger {
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+	return l.ResetNamed(name)
+}
+
+func (l *HCLogger) ResetNamed(name string) hclog.Logger {
+	sub := *l
+	sub.name = name
+	sub.logger = l.base.With(Component(name))
+	return &sub
+}
+
+// SetLevel changes level of the component of logger
+func (l *HCLogger) SetLevel(level hclog.Level) {
+	if level == hclog.NoLevel {
+		return
+	}
+	l.levels.Set(l.name, toSlogLevel(level))
+}
+
+func (l *HCLogger) GetLevel() hclog.Level {
+	return toHCLogLevel(l.levels.Level(l.name))
+}
+
+func (l *HCLogger) StandardLogger(opts *hclog.StandardLoggerOptions) *log.Logger {
+	return log.New(l.StandardWriter(opts), "", 0)
+}
+
+func (l *HCLogger) StandardWriter(opts *hclog.StandardLoggerOptions) io.Writer {
+	level := slog.LevelInfo
+	if opts != nil && opts.ForceLevel != hclog.NoLevel {
+		level = toSlogLevel(opts.ForceLevel)
+	}
+
+	return slog.NewLogLogger(l.logger.Handler(), level).Writer()
+}
+
+func (l *HCLogger) enabled(level hclog.Level) bool {
+	return l.logger.Enabled(context.Background(), toSlogLevel(level))
+}
+
+// appendArgs converts hclog key value pairs the same way hclog json format does
+func appendArgs(attrs []slog.Attr, args []any) []slog.Attr {
+	if len(args)%2 != 0 {
+		args = append(args[:len(args)-1:len(args)-1], hclog.MissingKey, args[len(args)-1])
+	}
+
+	for i := 0; i < len(args); i += 2 {
+		key, ok := args[i].(string)
+		if !ok {
+			key = fmt.Sprintf("%s", args[i])
+		}
+
+		value := args[i+1]
+		if format, ok := value.(hclog.Format); ok && len(format) > 0 {
+			value = fmt.Sprintf(fmt.Sprint(format[0]), format[1:]...)
+		}
+
+		attrs = append(attrs, slog.Any(key, value))
+	}
+
+	return attrs
+}
+
+func toSlogLevel(level hclog.Level) slog.Level {
+	switch level {
+	case hclog.Trace:
+		return LevelTrace
+	case hclog.Debug:
+		return slog.LevelDebug
+	case hclog.Warn:
+		return slog.LevelWarn
+	case hclog.Error, hclog.Off:
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
+
+func toHCLogLevel(level slog.Level) hclog.Level {
+	switch {
+	case level <= LevelTrace:
+		return hclog.Trace
+	case level <= slog.LevelDebug:
+		return hclog.Debug
+	case level <= slog.LevelInfo:
+		return hclog.Info
+	case level <= slog.LevelWarn:
+		return hclog.Warn
+	default:
+		return hclog.Error
+	}
+}
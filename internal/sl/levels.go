@@ -0,0 +1,125 @@
+package sl
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"strings"
+	"sync"
+)
+
+// Levels are global log level and levels of components which override it, they can be changed at runtime
+type Levels struct {
+	mu         *sync.RWMutex
+	global     slog.Level
+	components map[string]slog.Level
+}
+
+func NewLevels(global slog.Level, components map[string]slog.Level) *Levels {
+	return &Levels{
+		mu:         new(sync.RWMutex),
+		global:     global,
+		components: maps.Clone(components),
+	}
+}
+
+// Level is level of component, global level is used if component has no own one
+func (l *Levels) Level(component string) slog.Level {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	if level, ok := l.components[component]; ok {
+		return level
+	}
+	return l.global
+}
+
+// Set changes level of component or global level if component is empty
+func (l *Levels) Set(component string, level slog.Level) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if component == "" {
+		l.global = level
+		return
+	}
+	if l.components == nil {
+		l.components = make(map[string]slog.Level)
+	}
+	l.components[component] = level
+}
+
+// Reset makes component use global level again
+func (l *Levels) Reset(component string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.components, component)
+}
+
+func (l *Levels) List() (slog.Level, map[string]slog.Level) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return l.global, maps.Clone(l.components)
+}
+
+// ParseLevel accepts slog level names and trace in any case
+func ParseLevel(s string) (slog.Level, error) {
+	if strings.EqualFold(s, "trace") {
+		return LevelTrace, nil
+	}
+
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(s)); err != nil {
+		return 0, fmt.Errorf("unknown log level %q", s)
+	}
+	return level, nil
+}
+
+func LevelName(level slog.Level) string {
+	if level <= LevelTrace {
+		return "trace"
+	}
+	return strings.ToLower(level.String())
+}
+
+// LevelHandler filters records by level of component which logger is created for with Component
+type LevelHandler struct {
+	handler   slog.Handler
+	levels    *Levels
+	component string
+}
+
+func NewLevelHandler(handler slog.Handler, levels *Levels) *LevelHandler {
+	return &LevelHandler{
+		handler: handler,
+		levels:  levels,
+	}
+}
+
+func (h *LevelHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.levels.Level(h.component)
+}
+
+func (h *LevelHandler) Handle(ctx context.Context, record slog.Record) error {
+	return h.handler.Handle(ctx, record)
+}
+
+func (h *LevelHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	sub := *h
+	sub.handler = h.handler.WithAttrs(attrs)
+	for _, attr := range attrs {
+		if attr.Key == MessageComponent {
+			sub.component = attr.Value.String()
+		}
+	}
+	return &sub
+}
+
+func (h *LevelHandler) WithGroup(name string) slog.Handler {
+	sub := *h
+	sub.handler = h.handler.WithGroup(name)
+	return &sub
+}
@@ -0,0 +1,219 @@
+package sl
+
+import (
+	"bytes"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestLevels(t *testing.T) {
+	type change struct {
+		component string
+		level     slog.Level
+		reset     bool
+	}
+
+	tests := []struct {
+		name       string
+		global     slog.Level
+		components map[string]slog.Level
+		changes    []change
+		want       map[string]slog.Level
+	}{
+		{
+			name:   "global level is used without own one",
+			global: slog.LevelWarn,
+			want:   map[string]slog.Level{"a": slog.LevelWarn, "": slog.LevelWarn},
+		},
+		{
+			name:       "own level overrides global",
+			global:     slog.LevelInfo,
+			components: map[string]slog.Level{"a": slog.LevelDebug},
+			want:       map[string]slog.Level{"a": slog.LevelDebug, "b": slog.LevelInfo},
+		},
+		{
+			name:    "empty component sets global level",
+			global:  slog.LevelInfo,
+			changes: []change{{component: "", level: slog.LevelError}},
+			want:    map[string]slog.Level{"a": slog.LevelError},
+		},
+		{
+			name:       "set overrides configured level",
+			global:     slog.LevelInfo,
+			components: map[string]slog.Level{"a": slog.LevelDebug},
+			changes:    []change{{component: "a", level: LevelTrace}},
+			want:       map[string]slog.Level{"a": LevelTrace, "b": slog.LevelInfo},
+		},
+		{
+			name:       "reset returns to global level",
+			global:     slog.LevelInfo,
+			components: map[string]slog.Level{"a": slog.LevelDebug},
+			changes: []change{
+				{component: "a", reset: true},
+				{component: "", level: slog.LevelWarn},
+			},
+			want: map[string]slog.Level{"a": slog.LevelWarn},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			levels := NewLevels(tt.global, tt.components)
+
+			for _, c := range tt.changes {
+				if c.reset {
+					levels.Reset(c.component)
+				} else {
+					levels.Set(c.component, c.level)
+				}
+			}
+
+			for component, want := range tt.want {
+				if got := levels.Level(component); got != want {
+					t.Fatalf("Level(%q) = %s, want %s", component, LevelName(got), LevelName(want))
+				}
+			}
+		})
+	}
+}
+
+func TestLevelsDoNotShareConfiguredMap(t *testing.T) {
+	components := map[string]slog.Level{"a": slog.LevelDebug}
+
+	levels := NewLevels(slog.LevelInfo, components)
+	levels.Set("a", slog.LevelError)
+
+	if components["a"] != slog.LevelDebug {
+		t.Fatalf("configured level of a = %s, want it unchanged", LevelName(components["a"]))
+	}
+
+	_, listed := levels.List()
+	listed["a"] = slog.LevelWarn
+
+	if got := levels.Level("a"); got != slog.LevelError {
+		t.Fatalf("Level(a) = %s after changing listed map, want error", LevelName(got))
+	}
+}
+
+func TestParseLevel(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    slog.Level
+		wantErr bool
+	}{
+		{in: "trace", want: LevelTrace},
+		{in: "TRACE", want: LevelTrace},
+		{in: "debug", want: slog.LevelDebug},
+		{in: "Info", want: slog.LevelInfo},
+		{in: "warn", want: slog.LevelWarn},
+		{in: "error", want: slog.LevelError},
+		{in: "loud", wantErr: true},
+		{in: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.in, func(t *testing.T) {
+			got, err := ParseLevel(tt.in)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("ParseLevel(%q) = %s, want error", tt.in, LevelName(got))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseLevel(%q) error = %v", tt.in, err)
+			}
+			if got != tt.want {
+				t.Fatalf("ParseLevel(%q) = %s, want %s", tt.in, LevelName(got), LevelName(tt.want))
+			}
+			if again, _ := ParseLevel(LevelName(got)); again != got {
+				t.Fatalf("ParseLevel(LevelName(%s)) = %s", LevelName(got), LevelName(again))
+			}
+		})
+	}
+}
+
+func TestLevelHandler(t *testing.T) {
+	tests := []struct {
+		name      string
+		global    slog.Level
+		set       map[string]slog.Level
+		component string
+		group     bool
+		level     slog.Level
+		want      bool
+	}{
+		{name: "global level filters", global: slog.LevelInfo, component: "a", level: slog.LevelDebug, want: false},
+		{name: "global level passes", global: slog.LevelInfo, component: "a", level: slog.LevelInfo, want: true},
+		{
+			name:      "component level lowers global",
+			global:    slog.LevelInfo,
+			set:       map[string]slog.Level{"a": slog.LevelDebug},
+			component: "a",
+			level:     slog.LevelDebug,
+			want:      true,
+		},
+		{
+			name:      "component level raises global",
+			global:    slog.LevelDebug,
+			set:       map[string]slog.Level{"a": slog.LevelError},
+			component: "a",
+			level:     slog.LevelWarn,
+			want:      false,
+		},
+		{
+			name:      "level of other component does not apply",
+			global:    slog.LevelInfo,
+			set:       map[string]slog.Level{"b": slog.LevelDebug},
+			component: "a",
+			level:     slog.LevelDebug,
+			want:      false,
+		},
+		{
+			name:   "logger without component uses global",
+			global: slog.LevelInfo,
+			set:    map[string]slog.Level{"a": slog.LevelDebug},
+			level:  slog.LevelDebug,
+			want:   false,
+		},
+		{
+			name:      "component is kept in groups",
+			global:    slog.LevelInfo,
+			set:       map[string]slog.Level{"a": slog.LevelDebug},
+			component: "a",
+			group:     true,
+			level:     slog.LevelDebug,
+			want:      true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			// base handler passes everything, so only LevelHandler filters
+			base := slog.NewTextHandler(&out, &slog.HandlerOptions{Level: LevelTrace})
+			levels := NewLevels(tt.global, nil)
+
+			logger := slog.New(NewLevelHandler(base, levels))
+			if tt.component != "" {
+				logger = logger.With(Component(tt.component))
+			}
+			if tt.group {
+				logger = logger.WithGroup("request")
+			}
+
+			// levels are changed after logger is created like at runtime
+			for component, level := range tt.set {
+				levels.Set(component, level)
+			}
+
+			logger.Log(t.Context(), tt.level, "message")
+
+			if got := strings.Contains(out.String(), "message"); got != tt.want {
+				t.Fatalf("logged = %v, want %v, output %q", got, tt.want, out.String())
+			}
+		})
+	}
+}
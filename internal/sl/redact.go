@@ -0,0 +1,132 @@
+package sl
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync/atomic"
+)
+
+const (
+	// RedactPlain logs data as it is
+	RedactPlain = "plain"
+	// RedactTruncate logs first MaxLength bytes of data and its length
+	RedactTruncate = "truncate"
+	// RedactHash replaces data with its sha256, so equal data can be matched without revealing it
+	RedactHash = "hash"
+	// RedactOmit logs only length of data
+	RedactOmit = "omit"
+
+	redacted = "[REDACTED]"
+
+	defaultKeys      = RedactPlain
+	defaultValues    = RedactHash
+	defaultMaxLength = 16
+)
+
+// Secret is string which is masked when it is logged, printed or marshalled,
+// so configs with passwords and tokens can be logged with Conf, it is converted to string to be used
+type Secret string
+
+func (s Secret) LogValue() slog.Value {
+	return slog.StringValue(s.String())
+}
+
+func (s Secret) String() string {
+	if s == "" {
+		return ""
+	}
+	return redacted
+}
+
+func (s Secret) GoString() string {
+	return fmt.Sprintf("%q", s.String())
+}
+
+func (s Secret) MarshalText() ([]byte, error) {
+	return []byte(s.String()), nil
+}
+
+// RedactConfig is policy of logging keys and values of store, modes are plain, truncate, hash and omit
+type RedactConfig struct {
+	Keys   string
+	Values string
+	// MaxLength is amount of bytes kept by truncate mode
+	MaxLength int
+}
+
+var redaction atomic.Pointer[RedactConfig]
+
+func init() {
+	redaction.Store(&RedactConfig{Keys: defaultKeys, Values: defaultValues, MaxLength: defaultMaxLength})
+}
+
+// SetRedaction changes policy used by Key and Value, it is called once at startup before logging keys and values,
+// keys are plain and values are hashed by default
+func SetRedaction(conf RedactConfig) error {
+	if conf.Keys == "" {
+		conf.Keys = defaultKeys
+	}
+	if conf.Values == "" {
+		conf.Values = defaultValues
+	}
+	for _, mode := range []string{conf.Keys, conf.Values} {
+		switch mode {
+		case RedactPlain, RedactTruncate, RedactHash, RedactOmit:
+		default:
+			return fmt.Errorf("unknown redact mode %q", mode)
+		}
+	}
+	if conf.MaxLength < 0 {
+		return errors.New("max length cannot be negative")
+	}
+	if conf.MaxLength == 0 {
+		conf.MaxLength = defaultMaxLength
+	}
+
+	redaction.Store(&conf)
+
+	return nil
+}
+
+// Key is attribute with key of store which is redacted by policy of keys
+func Key(name, key string) slog.Attr {
+	return slog.Any(name, redactable{data: key})
+}
+
+// Value is attribute with value of store which is redacted by policy of values
+func Value(name, value string) slog.Attr {
+	return slog.Any(name, redactable{data: value, value: true})
+}
+
+type redactable struct {
+	data  string
+	value bool
+}
+
+// LogValue applies policy when record is handled, so data of disabled records is not hashed
+func (r redactable) LogValue() slog.Value {
+	conf := redaction.Load()
+
+	mode := conf.Keys
+	if r.value {
+		mode = conf.Values
+	}
+
+	switch mode {
+	case RedactPlain:
+		return slog.StringValue(r.data)
+	case RedactTruncate:
+		if len(r.data) <= conf.MaxLength {
+			return slog.StringValue(r.data)
+		}
+		return slog.StringValue(fmt.Sprintf("%s...(%d bytes)", r.data[:conf.MaxLength], len(r.data)))
+	case RedactHash:
+		sum := sha256.Sum256([]byte(r.data))
+		return slog.StringValue("sha256:" + hex.EncodeToString(sum[:]))
+	default:
+		return slog.StringValue(fmt.Sprintf("(%d bytes)", len(r.data)))
+	}
+}
@@ -0,0 +1,99 @@
+package sl
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+// sha256 of "value"
+const valueHash = "sha256:cd42404d52ad55ccfa9aca4adc828aa5800ad9d385a0671fbcbf724118320619"
+
+func TestRedaction(t *testing.T) {
+	tests := []struct {
+		name      string
+		conf      RedactConfig
+		attr      slog.Attr
+		want      string
+		wantError bool
+	}{
+		{name: "keys are plain by default", attr: Key("key", "users/1"), want: "users/1"},
+		{name: "values are hashed by default", attr: Value("value", "value"), want: valueHash},
+		{name: "plain value", conf: RedactConfig{Values: RedactPlain}, attr: Value("value", "value"), want: "value"},
+		{name: "hashed key", conf: RedactConfig{Keys: RedactHash}, attr: Key("key", "value"), want: valueHash},
+		{name: "omitted value", conf: RedactConfig{Values: RedactOmit}, attr: Value("value", "value"), want: "(5 bytes)"},
+		{
+			name: "truncated value",
+			conf: RedactConfig{Values: RedactTruncate, MaxLength: 3},
+			attr: Value("value", "value"),
+			want: "val...(5 bytes)",
+		},
+		{
+			name: "short value is not truncated",
+			conf: RedactConfig{Values: RedactTruncate, MaxLength: 5},
+			attr: Value("value", "value"),
+			want: "value",
+		},
+		{name: "key mode does not apply to values", conf: RedactConfig{Keys: RedactPlain}, attr: Value("value", "value"), want: valueHash},
+		{name: "unknown mode", conf: RedactConfig{Values: "mask"}, wantError: true},
+		{name: "negative max length", conf: RedactConfig{MaxLength: -1}, wantError: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Cleanup(func() {
+				_ = SetRedaction(RedactConfig{})
+			})
+
+			err := SetRedaction(tt.conf)
+			if tt.wantError {
+				if err == nil {
+					t.Fatal("SetRedaction() error = nil, want error")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SetRedaction() error = %v", err)
+			}
+
+			if got := tt.attr.Value.Resolve().String(); got != tt.want {
+				t.Fatalf("%s = %q, want %q", tt.attr.Key, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSecret(t *testing.T) {
+	type config struct {
+		User     string
+		Password Secret
+	}
+
+	conf := config{User: "admin", Password: "hunter2"}
+
+	var out bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&out, nil))
+	logger.Info("config", Conf(conf), slog.Any("password", conf.Password))
+
+	for name, got := range map[string]string{
+		"log":      out.String(),
+		"%v":       fmt.Sprintf("%v", conf),
+		"%+v":      fmt.Sprintf("%+v", conf),
+		"%#v":      fmt.Sprintf("%#v", conf),
+		"%s":       fmt.Sprintf("%s", conf.Password),
+		"String()": conf.Password.String(),
+	} {
+		if strings.Contains(got, "hunter2") {
+			t.Fatalf("%s reveals secret: %s", name, got)
+		}
+	}
+
+	if string(conf.Password) != "hunter2" {
+		t.Fatalf("converted secret = %q, want original", string(conf.Password))
+	}
+	if Secret("").String() != "" {
+		t.Fatalf("empty secret = %q, want empty", Secret("").String())
+	}
+}
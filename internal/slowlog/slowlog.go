@@ -0,0 +1,107 @@
+package slowlog
+
+import (
+	"context"
+	"errors"
+	"kvstore/internal/sl"
+	"log/slog"
+	"math/rand/v2"
+	"time"
+)
+
+const (
+	PhaseAuth        = "auth"
+	PhaseLeaderCheck = "leader_check"
+	PhaseRaftCommit  = "raft_commit"
+	PhaseFSMApply    = "fsm_apply"
+)
+
+type Config struct {
+	// Threshold is duration which operations are logged after, zero disables slow log
+	Threshold time.Duration
+	// SampleRate is probability of logging request and response payloads, zero disables sampling
+	SampleRate float64
+}
+
+// Log records operations which take longer than threshold together with duration of their phases
+type Log struct {
+	logger     *slog.Logger
+	threshold  time.Duration
+	sampleRate float64
+}
+
+func New(logger *slog.Logger, conf Config) (*Log, error) {
+	if logger == nil {
+		return nil, errors.New("logger required")
+	}
+
+	logger = logger.With(sl.Component("slowlog.Log"))
+
+	if conf.Threshold < 0 {
+		return nil, errors.New("threshold cannot be negative")
+	}
+	if conf.SampleRate < 0 || conf.SampleRate > 1 {
+		return nil, errors.New("sample rate must be between 0 and 1")
+	}
+
+	logger.Debug("created successfully", sl.Conf(conf))
+
+	return &Log{
+		logger:     logger,
+		threshold:  conf.Threshold,
+		sampleRate: conf.SampleRate,
+	}, nil
+}
+
+// Begin starts timing of operation unless context already belongs to one,
+// returned function logs operation if it has taken longer than threshold
+func (l *Log) Begin(ctx context.Context, operation string) (context.Context, func(err error)) {
+	if l.threshold <= 0 {
+		return ctx, func(error) {}
+	}
+	if _, ok := ctx.Value(timingsKey{}).(*timings); ok {
+		return ctx, func(error) {}
+	}
+
+	t := newTimings()
+	ctx = context.WithValue(ctx, timingsKey{}, t)
+
+	start := time.Now()
+
+	return ctx, func(err error) {
+		elapsed := time.Since(start)
+		if elapsed < l.threshold {
+			return
+		}
+
+		attrs := []any{
+			slog.String("operation", operation),
+			slog.Duration("duration", elapsed),
+			t.attr(),
+		}
+		if err != nil {
+			attrs = append(attrs, sl.Error(err))
+		}
+
+		l.logger.WarnContext(ctx, "slow operation", attrs...)
+	}
+}
+
+// Sampled decides whether payloads of the current request should be logged
+func (l *Log) Sampled() bool {
+	return l.sampleRate > 0 && rand.Float64() < l.sampleRate
+}
+
+// Sample logs payloads described by caller, so it decides which fields are logged and how they are redacted
+func (l *Log) Sample(ctx context.Context, method string, request, response []slog.Attr, err error) {
+	attrs := []any{
+		slog.String("method", method),
+		slog.Attr{Key: "request", Value: slog.GroupValue(request...)},
+		slog.Attr{Key: "response", Value: slog.GroupValue(response...)},
+	}
+	if err != nil {
+		attrs = append(attrs, sl.Error(err))
+	}
+
+	l.logger.InfoContext(ctx, "sampled request", attrs...)
+}
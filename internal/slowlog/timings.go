@@ -0,0 +1,65 @@
+package slowlog
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+type timingsKey struct{}
+
+type phase struct {
+	name     string
+	duration time.Duration
+}
+
+// timings are durations of phases of one operation in order they have finished
+type timings struct {
+	mu     *sync.Mutex
+	phases []phase
+}
+
+func newTimings() *timings {
+	return &timings{mu: new(sync.Mutex)}
+}
+
+func (t *timings) add(name string, duration time.Duration) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.phases = append(t.phases, phase{name: name, duration: duration})
+}
+
+func (t *timings) attr() slog.Attr {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	attrs := make([]any, 0, len(t.phases))
+	for _, p := range t.phases {
+		attrs = append(attrs, slog.Duration(p.name, p.duration))
+	}
+
+	return slog.Group("breakdown", attrs...)
+}
+
+// Observe records duration of phase if context belongs to timed operation
+func Observe(ctx context.Context, name string, duration time.Duration) {
+	if t, ok := ctx.Value(timingsKey{}).(*timings); ok {
+		t.add(name, duration)
+	}
+}
+
+// Phase starts phase, returned function records its duration
+func Phase(ctx context.Context, name string) func() {
+	t, ok := ctx.Value(timingsKey{}).(*timings)
+	if !ok {
+		return func() {}
+	}
+
+	start := time.Now()
+
+	return func() {
+		t.add(name, time.Since(start))
+	}
+}
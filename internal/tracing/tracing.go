@@ -0,0 +1,153 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"io"
+	"kvstore/internal/sl"
+	"log/slog"
+	"os"
+)
+
+const (
+	ExporterNone   = "none"
+	ExporterOTLP   = "otlp"
+	ExporterStdout = "stdout"
+	ExporterFile   = "file"
+
+	defaultServiceName = "kvstore"
+)
+
+type Config struct {
+	// Exporter is one of none, otlp, stdout and file
+	Exporter string
+	// Endpoint is host:port of otlp grpc collector
+	Endpoint string
+	Insecure bool
+	// File is path of file which spans are appended to by file exporter
+	File        string
+	SampleRatio float64
+	ServiceName string
+	NodeID      string
+}
+
+// Tracing owns tracer provider which is installed globally, so packages create spans with otel.Tracer
+type Tracing struct {
+	logger   *slog.Logger
+	provider *sdktrace.TracerProvider
+	closer   io.Closer
+}
+
+// New installs global propagator and tracer provider, nothing is exported if exporter is none
+func New(ctx context.Context, logger *slog.Logger, conf Config) (*Tracing, error) {
+	if logger == nil {
+		return nil, errors.New("logger required")
+	}
+
+	logger = logger.With(sl.Component("tracing.Tracing"))
+
+	if conf.Exporter == "" {
+		conf.Exporter = ExporterNone
+	}
+	if conf.ServiceName == "" {
+		conf.ServiceName = defaultServiceName
+	}
+	if conf.SampleRatio <= 0 || conf.SampleRatio > 1 {
+		conf.SampleRatio = 1
+	}
+
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	t := &Tracing{logger: logger}
+
+	var (
+		exporter sdktrace.SpanExporter
+		err      error
+	)
+
+	switch conf.Exporter {
+	case ExporterNone:
+		logger.Debug("created successfully", sl.Conf(conf))
+		return t, nil
+	case ExporterOTLP:
+		if conf.Endpoint == "" {
+			return nil, errors.New("endpoint required for otlp exporter")
+		}
+		opts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(conf.Endpoint)}
+		if conf.Insecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		exporter, err = otlptracegrpc.New(ctx, opts...)
+	case ExporterStdout:
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+	case ExporterFile:
+		if conf.File == "" {
+			return nil, errors.New("file required for file exporter")
+		}
+		var file *os.File
+		file, err = os.OpenFile(conf.File, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+		if err != nil {
+			return nil, fmt.Errorf("cannot open traces file: %w", err)
+		}
+		t.closer = file
+		exporter, err = stdouttrace.New(stdouttrace.WithWriter(file))
+	default:
+		return nil, fmt.Errorf("unknown exporter %q", conf.Exporter)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("cannot create %s exporter: %w", conf.Exporter, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceName(conf.ServiceName),
+		semconv.ServiceInstanceID(conf.NodeID),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("cannot create resource: %w", err)
+	}
+
+	t.provider = sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(conf.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(t.provider)
+
+	logger.Debug("created successfully", sl.Conf(conf))
+
+	return t, nil
+}
+
+// Shutdown flushes spans which are not exported yet
+func (t *Tracing) Shutdown(ctx context.Context) error {
+	if t.provider == nil {
+		return nil
+	}
+
+	t.logger.Info("shutting down")
+
+	err := t.provider.Shutdown(ctx)
+	if t.closer != nil {
+		err = errors.Join(err, t.closer.Close())
+	}
+	if err != nil {
+		return err
+	}
+
+	t.logger.Info("shut down gracefully")
+
+	return nil
+}